@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/josimarz/fc-eda-challenge/configs"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/kafka"
+)
+
+const usage = `usage: dlq <command> <topic> [position...]
+
+commands:
+  list <topic>                   show the messages in <topic>.dlq
+  inspect <topic> <position>     print the headers and value of a dead letter
+  replay <topic> [position...]   publish dead letters back to <topic> (the ones not replayed yet when no position is given)
+
+positions are written as <partition>:<offset> within <topic>.dlq`
+
+var (
+	config    *configs.Config
+	configMap *ckafka.ConfigMap
+)
+
+func main() {
+	if len(os.Args) < 3 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+
+	err := loadConfig()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	configMap = &ckafka.ConfigMap{
+		"bootstrap.servers": config.KafkaDSN,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := run(ctx, os.Args[1], os.Args[2], os.Args[3:]); err != nil {
+		log.Fatal(err.Error())
+	}
+}
+
+func loadConfig() (err error) {
+	config, err = configs.LoadConfig(".")
+	return err
+}
+
+func run(ctx context.Context, command, topic string, positions []string) error {
+	letters, err := kafka.ReadDeadLetters(ctx, configMap, topic)
+	if err != nil {
+		return err
+	}
+	switch command {
+	case "list":
+		replayed, err := kafka.ReplayedOffsets(ctx, configMap, topic)
+		if err != nil {
+			return err
+		}
+		for _, dl := range letters {
+			status := "pending"
+			if isReplayed(dl, replayed) {
+				status = "replayed"
+			}
+			fmt.Printf("%s\t%s\t%s[%d]@%d\t%d attempts\t%s\t%s\n", position(dl), status, dl.Topic, dl.Partition, dl.Offset, dl.Attempts, dl.FailedAt.Format(time.RFC3339), dl.Error)
+		}
+		return nil
+	case "inspect":
+		if len(positions) != 1 {
+			return fmt.Errorf("missing position\n%s", usage)
+		}
+		selected, err := selectLetters(letters, positions)
+		if err != nil {
+			return err
+		}
+		dl := selected[0]
+		fmt.Printf("position: %s\nsource: %s[%d]@%d\nattempts: %d\nfailed at: %s\nerror: %s\n", position(dl), dl.Topic, dl.Partition, dl.Offset, dl.Attempts, dl.FailedAt.Format(time.RFC3339Nano), dl.Error)
+		fmt.Printf("key: %s\nheaders:\n", dl.Message.Key)
+		for _, header := range dl.Message.Headers {
+			fmt.Printf("  %s: %s\n", header.Key, header.Value)
+		}
+		fmt.Printf("value:\n%s\n", dl.Message.Value)
+		return nil
+	case "replay":
+		var selected []*kafka.DeadLetter
+		if len(positions) > 0 {
+			if selected, err = selectLetters(letters, positions); err != nil {
+				return err
+			}
+		} else {
+			replayed, err := kafka.ReplayedOffsets(ctx, configMap, topic)
+			if err != nil {
+				return err
+			}
+			for _, dl := range letters {
+				if !isReplayed(dl, replayed) {
+					selected = append(selected, dl)
+				}
+			}
+		}
+		producer, err := kafka.NewProducer(configMap, "dlq", nil)
+		if err != nil {
+			return err
+		}
+		defer producer.Close(ctx)
+		for _, dl := range selected {
+			if err := producer.Replay(ctx, dl); err != nil {
+				return err
+			}
+			fmt.Printf("[%s] %s replayed to %s\n", kafka.DeadLetterTopic(topic), position(dl), dl.Topic)
+			if len(positions) > 0 {
+				continue
+			}
+			if err := kafka.MarkReplayed(ctx, configMap, dl); err != nil {
+				return err
+			}
+		}
+		if len(selected) == 0 {
+			fmt.Printf("[%s] nothing to replay\n", kafka.DeadLetterTopic(topic))
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n%s", command, usage)
+	}
+}
+
+func position(dl *kafka.DeadLetter) string {
+	return fmt.Sprintf("%d:%d", dl.Message.TopicPartition.Partition, dl.Message.TopicPartition.Offset)
+}
+
+func isReplayed(dl *kafka.DeadLetter, replayed map[int32]int64) bool {
+	offset, ok := replayed[dl.Message.TopicPartition.Partition]
+	return ok && int64(dl.Message.TopicPartition.Offset) < offset
+}
+
+func selectLetters(letters []*kafka.DeadLetter, positions []string) ([]*kafka.DeadLetter, error) {
+	byPosition := make(map[string]*kafka.DeadLetter)
+	for _, dl := range letters {
+		byPosition[position(dl)] = dl
+	}
+	var selected []*kafka.DeadLetter
+	for _, p := range positions {
+		dl, ok := byPosition[strings.TrimSpace(p)]
+		if !ok {
+			return nil, fmt.Errorf("no dead letter at position %s", p)
+		}
+		selected = append(selected, dl)
+	}
+	return selected, nil
+}
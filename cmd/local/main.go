@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/josimarz/fc-eda-challenge/configs"
+	eventhandling "github.com/josimarz/fc-eda-challenge/internal/event_handling"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/messaging"
+	"github.com/josimarz/fc-eda-challenge/internal/service"
+	"github.com/josimarz/fc-eda-challenge/pkg/events/codec"
+)
+
+var (
+	config         *configs.Config
+	walletCoreDB   *sql.DB
+	transactionsDB *sql.DB
+	walletCore     *service.WalletCore
+	transactions   *service.Transactions
+	broker         *messaging.MemoryBroker
+	codecs         *codec.Registry
+	topicCodecs    map[string]codec.Codec
+)
+
+func main() {
+	err := loadConfig()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	err = loadCodecs()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	err = openWalletCoreDB()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	err = openTransactionsDB()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	err = startServices()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	go walletCore.StartPaymentRequestExpirer()
+	go walletCore.StartProjections()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var consumers sync.WaitGroup
+	consumers.Add(2)
+	go func() {
+		defer consumers.Done()
+		broker.Subscribe("wallet", []string{"transactions"}).Run(ctx, transactions.HandleTransactionMessage)
+	}()
+	go func() {
+		defer consumers.Done()
+		broker.Subscribe("wallet", []string{"balances"}).Run(ctx, walletCore.HandleBalancesMessage)
+	}()
+	if err := walletCore.StartServer(ctx); err != nil {
+		log.Fatal(err.Error())
+	}
+	consumers.Wait()
+	shutdown()
+}
+
+func loadConfig() (err error) {
+	config, err = configs.LoadConfig(".")
+	return err
+}
+
+func loadCodecs() (err error) {
+	codecs, err = eventhandling.NewCodecRegistry(config.SchemasPath)
+	if err != nil {
+		return err
+	}
+	topicCodecs, err = codecs.ForTopics(config.KafkaCodecs)
+	return err
+}
+
+func openWalletCoreDB() (err error) {
+	walletCoreDB, err = sql.Open("mysql", config.WalletCoreDSN)
+	return err
+}
+
+func openTransactionsDB() (err error) {
+	transactionsDB, err = sql.Open("mysql", config.TransactionsDSN)
+	return err
+}
+
+// startServices connects both services to a single in-memory broker, so the
+// whole flow runs in this process without Kafka.
+func startServices() (err error) {
+	broker = messaging.NewMemoryBroker(3)
+	walletCore, err = service.NewWalletCore(config, walletCoreDB, codecs, broker.Publisher("walletcore", topicCodecs))
+	if err != nil {
+		return err
+	}
+	transactions, err = service.NewTransactions(config, walletCoreDB, transactionsDB, codecs, broker.Publisher("transactions", topicCodecs))
+	return err
+}
+
+func shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := walletCore.Shutdown(ctx); err != nil {
+		log.Println(err.Error())
+	}
+	if err := transactions.Shutdown(ctx); err != nil {
+		log.Println(err.Error())
+	}
+}
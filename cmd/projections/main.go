@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/josimarz/fc-eda-challenge/configs"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/database/mysql"
+	"github.com/josimarz/fc-eda-challenge/pkg/eventstore"
+	"github.com/josimarz/fc-eda-challenge/pkg/projection"
+)
+
+const usage = `usage: projections <command> [name]
+
+commands:
+  list             show every projection and its checkpoint
+  reset <name>     clear the read model and its checkpoint
+  rebuild <name>   reset the read model and replay the event store into it`
+
+var (
+	config       *configs.Config
+	walletCoreDB *sql.DB
+	eventStore   eventstore.Store
+	projections  map[string]*projection.Runner
+	names        []string
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+
+	err := loadConfig()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	err = openWalletCoreDB()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	if config.AccountStore != "eventsourced" {
+		log.Fatal(`customer_balance is built from account events, which are only recorded with ACCOUNT_STORE="eventsourced"`)
+	}
+
+	createProjections()
+
+	if err := run(context.Background(), os.Args[1], os.Args[2:]); err != nil {
+		log.Fatal(err.Error())
+	}
+}
+
+func loadConfig() (err error) {
+	config, err = configs.LoadConfig(".")
+	return err
+}
+
+func openWalletCoreDB() (err error) {
+	walletCoreDB, err = sql.Open("mysql", config.WalletCoreDSN)
+	return err
+}
+
+func createProjections() {
+	eventStore = mysql.NewEventStore(walletCoreDB)
+	checkpoints := mysql.NewProjectionCheckpointStore(walletCoreDB)
+	projections = make(map[string]*projection.Runner)
+	register(mysql.NewCustomerBalanceProjection(walletCoreDB), checkpoints)
+}
+
+func register(p projection.Projection, checkpoints projection.CheckpointStore) {
+	projections[p.Name()] = projection.NewRunner(p, checkpoints)
+	names = append(names, p.Name())
+}
+
+func run(ctx context.Context, command string, args []string) error {
+	switch command {
+	case "list":
+		for _, name := range names {
+			position, err := projections[name].Position(ctx)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s\t%d\n", name, position)
+		}
+		return nil
+	case "reset":
+		runner, err := find(args)
+		if err != nil {
+			return err
+		}
+		if err := runner.Reset(ctx); err != nil {
+			return err
+		}
+		fmt.Printf("[%s] reset\n", runner.Name())
+		return nil
+	case "rebuild":
+		runner, err := find(args)
+		if err != nil {
+			return err
+		}
+		applied, err := runner.Rebuild(ctx, eventStore)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("[%s] rebuilt from %d events\n", runner.Name(), applied)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n%s", command, usage)
+	}
+}
+
+func find(args []string) (*projection.Runner, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("missing projection name\n%s", usage)
+	}
+	runner, ok := projections[args[0]]
+	if !ok {
+		return nil, fmt.Errorf("unknown projection %q", args[0])
+	}
+	return runner, nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/josimarz/fc-eda-challenge/configs"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/kafka"
+)
+
+const usage = `usage: topics <command>
+
+commands:
+  plan    show the changes needed to match the topics declared in TOPICS_PATH
+  apply   show and apply those changes (creates topics, adds partitions, updates configs)`
+
+var (
+	config    *configs.Config
+	configMap *ckafka.ConfigMap
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+
+	err := loadConfig()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	configMap = &ckafka.ConfigMap{
+		"bootstrap.servers": config.KafkaDSN,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := run(ctx, os.Args[1]); err != nil {
+		log.Fatal(err.Error())
+	}
+}
+
+func loadConfig() (err error) {
+	config, err = configs.LoadConfig(".")
+	return err
+}
+
+func run(ctx context.Context, command string) error {
+	if command != "plan" && command != "apply" {
+		return fmt.Errorf("unknown command %q\n%s", command, usage)
+	}
+	topics, err := kafka.LoadTopics(config.TopicsPath)
+	if err != nil {
+		return err
+	}
+	manager, err := kafka.NewTopicManager(configMap)
+	if err != nil {
+		return err
+	}
+	defer manager.Close()
+	plan, err := manager.Plan(ctx, topics)
+	if err != nil {
+		return err
+	}
+	if plan.Empty() {
+		fmt.Println("topics are up to date")
+		return nil
+	}
+	for _, change := range plan.Changes {
+		fmt.Println(change)
+	}
+	if command == "plan" {
+		return nil
+	}
+	if err := manager.Apply(ctx, plan); err != nil {
+		return err
+	}
+	fmt.Printf("%d changes applied\n", len(plan.Changes))
+	return nil
+}
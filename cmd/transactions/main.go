@@ -1,31 +1,41 @@
 package main
 
 import (
+	"context"
 	"database/sql"
-	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/josimarz/fc-eda-challenge/configs"
 	eventhandling "github.com/josimarz/fc-eda-challenge/internal/event_handling"
-	"github.com/josimarz/fc-eda-challenge/internal/gateway"
-	"github.com/josimarz/fc-eda-challenge/internal/infra/database/mysql"
 	"github.com/josimarz/fc-eda-challenge/internal/infra/kafka"
-	"github.com/josimarz/fc-eda-challenge/internal/usecase"
-	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/messaging"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/nats"
+	"github.com/josimarz/fc-eda-challenge/internal/service"
+	"github.com/josimarz/fc-eda-challenge/pkg/events/codec"
+	cnats "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 )
 
 var (
-	config                   *configs.Config
-	walletCoreDB             *sql.DB
-	transactionsDB           *sql.DB
-	accountGateway           gateway.AccountGateway
-	transactionGateway       gateway.TransactionGateway
-	createTransactionUseCase *usecase.CreateTransactionUseCase
-	producer                 *kafka.Producer
-	consumer                 *kafka.Consumer
-	eventDispatcher          *events.EventDispatcher
+	config         *configs.Config
+	walletCoreDB   *sql.DB
+	transactionsDB *sql.DB
+	transactions   *service.Transactions
+	producer       *kafka.Producer
+	publisher      messaging.Publisher
+	subscriber     messaging.Subscriber
+	natsConn       *cnats.Conn
+	jetStream      jetstream.JetStream
+	codecs         *codec.Registry
+	topicCodecs    map[string]codec.Codec
 )
 
 func main() {
@@ -34,6 +44,11 @@ func main() {
 		log.Fatal(err.Error())
 	}
 
+	err = loadCodecs()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
 	err = openWalletCoreDB()
 	if err != nil {
 		log.Fatal(err.Error())
@@ -44,10 +59,22 @@ func main() {
 		log.Fatal(err.Error())
 	}
 
-	startEventProducer()
-	createGateways()
-	createUseCases()
-	startEventConsumer()
+	err = startEventProducer()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	transactions, err = service.NewTransactions(config, walletCoreDB, transactionsDB, codecs, publisher)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := startEventConsumer(ctx); err != nil {
+		log.Println(err.Error())
+	}
+	shutdown()
 }
 
 func loadConfig() (err error) {
@@ -55,6 +82,15 @@ func loadConfig() (err error) {
 	return err
 }
 
+func loadCodecs() (err error) {
+	codecs, err = eventhandling.NewCodecRegistry(config.SchemasPath)
+	if err != nil {
+		return err
+	}
+	topicCodecs, err = codecs.ForTopics(config.KafkaCodecs)
+	return err
+}
+
 func openWalletCoreDB() (err error) {
 	walletCoreDB, err = sql.Open("mysql", config.WalletCoreDSN)
 	return err
@@ -65,38 +101,121 @@ func openTransactionsDB() (err error) {
 	return err
 }
 
-func startEventProducer() {
-	configMap := ckafka.ConfigMap{
-		"bootstrap.servers": config.KafkaDSN,
-		"group.id":          "wallet",
+func startEventProducer() (err error) {
+	switch config.MessageBroker {
+	case "nats":
+		publisher, err = newNatsPublisher()
+	case "memory":
+		err = errors.New(`MESSAGE_BROKER="memory" runs both services in a single process, start cmd/local instead`)
+	default:
+		publisher, err = newKafkaPublisher()
 	}
-	producer = kafka.NewProducer(&configMap)
-	eventDispatcher = events.NewEventDispatcher()
-	eventDispatcher.Register("balances.updated", eventhandling.NewBalancesUpdatedHandler(producer))
+	return err
 }
 
-func startEventConsumer() {
+func newKafkaPublisher() (messaging.Publisher, error) {
+	if err := provisionTopics(); err != nil {
+		return nil, err
+	}
 	configMap := ckafka.ConfigMap{
 		"bootstrap.servers": config.KafkaDSN,
-		"group.id":          "wallet",
-	}
-	consumer = kafka.NewConsumer(&configMap, []string{"transactions"})
-	ch := make(chan *ckafka.Message)
-	go consumer.Consume(ch)
-	for {
-		message := <-ch
-		var input usecase.CreateTransactionInput
-		if err := json.Unmarshal(message.Value, &input); err == nil {
-			createTransactionUseCase.Execute(&input)
-		}
+		"partitioner":       "murmur2_random",
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
 	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	producer, err = kafka.NewTransactionalProducer(ctx, &configMap, "transactions", topicCodecs, "transactions-"+hostname)
+	if err != nil {
+		return nil, err
+	}
+	return producer, nil
 }
 
-func createGateways() {
-	accountGateway = mysql.NewAccountGateway(walletCoreDB)
-	transactionGateway = mysql.NewTransactionGateway(transactionsDB)
+func provisionTopics() error {
+	topics, err := kafka.LoadTopics(config.TopicsPath)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	changes, err := kafka.EnsureTopics(ctx, &ckafka.ConfigMap{"bootstrap.servers": config.KafkaDSN}, topics)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		fmt.Printf("[Topics] %s\n", change)
+	}
+	return nil
+}
+
+func newNatsPublisher() (messaging.Publisher, error) {
+	if err := connectNats(); err != nil {
+		return nil, err
+	}
+	return nats.NewPublisher(jetStream, "transactions", topicCodecs), nil
+}
+
+func connectNats() (err error) {
+	natsConn, err = cnats.Connect(config.NatsURL)
+	if err != nil {
+		return err
+	}
+	jetStream, err = jetstream.New(natsConn)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return nats.EnsureStreams(ctx, jetStream, []string{"transactions", "balances"})
 }
 
-func createUseCases() {
-	createTransactionUseCase = usecase.NewCreateTransactionUseCase(transactionGateway, accountGateway, eventDispatcher)
+func startEventConsumer(ctx context.Context) (err error) {
+	if config.MessageBroker == "nats" {
+		subscriber, err = nats.NewSubscriber(ctx, jetStream, "wallet", []string{"transactions"})
+	} else {
+		subscriber, err = newKafkaConsumer()
+	}
+	if err != nil {
+		return err
+	}
+	defer subscriber.Close()
+	if err := subscriber.Run(ctx, transactions.HandleTransactionMessage); err != nil {
+		return err
+	}
+	fmt.Println("[Consumer] Shutting down")
+	return nil
+}
+
+func newKafkaConsumer() (messaging.Subscriber, error) {
+	configMap := ckafka.ConfigMap{
+		"bootstrap.servers":  config.KafkaDSN,
+		"group.id":           "wallet",
+		"enable.auto.commit": false,
+		"isolation.level":    "read_committed",
+	}
+	consumer, err := kafka.NewConsumer(&configMap, []string{"transactions"},
+		kafka.WithTransactions(producer),
+		kafka.WithRetryTopics(producer, kafka.DefaultRetryTiers...),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return consumer, nil
+}
+
+func shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := transactions.Shutdown(ctx); err != nil {
+		log.Println(err.Error())
+	}
+	if err := publisher.Close(ctx); err != nil {
+		log.Println(err.Error())
+	}
+	if natsConn != nil {
+		natsConn.Close()
+	}
 }
@@ -3,53 +3,38 @@ package main
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/josimarz/fc-eda-challenge/configs"
 	eventhandling "github.com/josimarz/fc-eda-challenge/internal/event_handling"
-	"github.com/josimarz/fc-eda-challenge/internal/gateway"
-	"github.com/josimarz/fc-eda-challenge/internal/infra/database/mysql"
 	"github.com/josimarz/fc-eda-challenge/internal/infra/kafka"
-	"github.com/josimarz/fc-eda-challenge/internal/infra/webserver"
-	"github.com/josimarz/fc-eda-challenge/internal/usecase"
-	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/messaging"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/nats"
+	"github.com/josimarz/fc-eda-challenge/internal/service"
+	"github.com/josimarz/fc-eda-challenge/pkg/events/codec"
+	cnats "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 )
 
 var (
-	config                      *configs.Config
-	server                      *webserver.Server
-	walletCoreDB                *sql.DB
-	customerGateway             gateway.CustomerGateway
-	accountGateway              gateway.AccountGateway
-	createCustomerUseCase       *usecase.CreateCustomerUseCase
-	findCustomerUseCase         *usecase.FindCustomerUseCase
-	listCustomersUseCase        *usecase.ListCustomersUseCase
-	updateCustomerUseCase       *usecase.UpdateCustomerUseCase
-	deleteCustomersUseCase      *usecase.DeleteCustomerUseCase
-	createAccountUseCase        *usecase.CreateAccountUseCase
-	listCustomerAccountsUseCase *usecase.ListCustomerAccountsUseCase
-	depositUseCase              *usecase.DepositUseCase
-	withdrawUseCase             *usecase.WithdrawUseCase
-	showAccountBalanceUseCase   *usecase.ShowAccountBalanceUseCase
-	createCustomerHandler       *webserver.CreateCustomerHandler
-	findCustomerHandler         *webserver.FindCustomerHandler
-	listCustomersHandler        *webserver.ListCustomersHandler
-	updateCustomerHandler       *webserver.UpdateCustomerHandler
-	deleteCustomerHandler       *webserver.DeleteCustomerHandler
-	createAccountHandler        *webserver.CreateAccountHandler
-	listCustomerAccountsHandler *webserver.ListCustomerAccountsHandler
-	depositHandler              *webserver.DepositHandler
-	withdrawHandler             *webserver.WithdrawHandler
-	showAccountBalanceHandler   *webserver.ShowAccountBalanceHandler
-	createTransactionHandler    *webserver.CreateTransactionHandler
-	producer                    *kafka.Producer
-	consumer                    *kafka.Consumer
-	eventDispatcher             *events.EventDispatcher
+	config       *configs.Config
+	walletCoreDB *sql.DB
+	walletCore   *service.WalletCore
+	producer     *kafka.Producer
+	publisher    messaging.Publisher
+	subscriber   messaging.Subscriber
+	natsConn     *cnats.Conn
+	jetStream    jetstream.JetStream
+	codecs       *codec.Registry
+	topicCodecs  map[string]codec.Codec
 )
 
 func main() {
@@ -58,20 +43,43 @@ func main() {
 		log.Fatal(err.Error())
 	}
 
+	err = loadCodecs()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
 	err = openWalletCoreDB()
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
-	startEventProducer()
-	go startEventConsumer()
-	createGateways()
-	createUseCases()
-	createHandlers()
+	err = startEventProducer()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	walletCore, err = service.NewWalletCore(config, walletCoreDB, codecs, publisher)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
 
-	if err := startServer(); err != nil {
+	go walletCore.StartPaymentRequestExpirer()
+	go walletCore.StartProjections()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	consumed := make(chan struct{})
+	go func() {
+		defer close(consumed)
+		if err := startEventConsumer(ctx); err != nil {
+			log.Fatal(err.Error())
+		}
+	}()
+	if err := walletCore.StartServer(ctx); err != nil {
 		log.Fatal(err.Error())
 	}
+	<-consumed
+	shutdown()
 }
 
 func loadConfig() (err error) {
@@ -79,130 +87,123 @@ func loadConfig() (err error) {
 	return err
 }
 
+func loadCodecs() (err error) {
+	codecs, err = eventhandling.NewCodecRegistry(config.SchemasPath)
+	if err != nil {
+		return err
+	}
+	topicCodecs, err = codecs.ForTopics(config.KafkaCodecs)
+	return err
+}
+
 func openWalletCoreDB() (err error) {
 	walletCoreDB, err = sql.Open("mysql", config.WalletCoreDSN)
 	return err
 }
 
-func startEventProducer() {
-	configMap := ckafka.ConfigMap{
-		"bootstrap.servers": config.KafkaDSN,
-		"group.id":          "wallet",
+func startEventProducer() (err error) {
+	switch config.MessageBroker {
+	case "nats":
+		publisher, err = newNatsPublisher()
+	case "memory":
+		err = errors.New(`MESSAGE_BROKER="memory" runs both services in a single process, start cmd/local instead`)
+	default:
+		publisher, err = newKafkaPublisher()
 	}
-	producer = kafka.NewProducer(&configMap)
-	eventDispatcher = events.NewEventDispatcher()
-	eventDispatcher.Register("transaction.created", eventhandling.NewTransactionCreatedHandler(producer))
+	return err
 }
 
-func startEventConsumer() {
+func newKafkaPublisher() (messaging.Publisher, error) {
+	if err := provisionTopics(); err != nil {
+		return nil, err
+	}
 	configMap := ckafka.ConfigMap{
-		"bootstrap.servers": config.KafkaDSN,
-		"group.id":          "wallet",
+		"bootstrap.servers":  config.KafkaDSN,
+		"enable.idempotence": true,
+		"partitioner":        "murmur2_random",
 	}
-	client, err := ckafka.NewAdminClient(&configMap)
+	var err error
+	producer, err = kafka.NewProducer(&configMap, "walletcore", topicCodecs)
 	if err != nil {
-		log.Fatal(err.Error())
+		return nil, err
 	}
-	maxDur, err := time.ParseDuration("60s")
+	return producer, nil
+}
+
+func provisionTopics() error {
+	topics, err := kafka.LoadTopics(config.TopicsPath)
 	if err != nil {
-		log.Fatal(err.Error())
+		return err
 	}
-	topics := []ckafka.TopicSpecification{
-		{
-			Topic:             "transactions",
-			NumPartitions:     1,
-			ReplicationFactor: 1,
-		},
-		{
-			Topic:             "balances",
-			NumPartitions:     1,
-			ReplicationFactor: 1,
-		},
-	}
-	results, err := client.CreateTopics(context.Background(), topics, ckafka.SetAdminOperationTimeout(maxDur))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	changes, err := kafka.EnsureTopics(ctx, &ckafka.ConfigMap{"bootstrap.servers": config.KafkaDSN}, topics)
 	if err != nil {
-		log.Fatal(err.Error())
+		return err
 	}
-	for _, result := range results {
-		fmt.Printf("%s\n", result)
-	}
-	consumer = kafka.NewConsumer(&configMap, []string{"balances"})
-	ch := make(chan *ckafka.Message)
-	go consumer.Consume(ch)
-	for {
-		message := <-ch
-		output := usecase.CreateTransactionOutput{}
-		if err := json.Unmarshal(message.Value, &output); err == nil {
-			depositInput := &usecase.DepositInput{
-				Id:     output.To.Id,
-				Amount: output.Amount,
-			}
-			if _, err := depositUseCase.Execute(depositInput); err != nil {
-				continue
-			}
-			withdrawInput := &usecase.WithdrawInput{
-				Id:     output.From.Id,
-				Amount: output.Amount,
-			}
-			if _, err := withdrawUseCase.Execute(withdrawInput); err != nil {
-				continue
-			}
-		}
+	for _, change := range changes {
+		fmt.Printf("[Topics] %s\n", change)
 	}
+	return nil
 }
 
-func createGateways() {
-	customerGateway = mysql.NewCustomerGateway(walletCoreDB)
-	accountGateway = mysql.NewAccountGateway(walletCoreDB)
+func newNatsPublisher() (messaging.Publisher, error) {
+	if err := connectNats(); err != nil {
+		return nil, err
+	}
+	return nats.NewPublisher(jetStream, "walletcore", topicCodecs), nil
 }
 
-func createUseCases() {
-	createCustomerUseCase = usecase.NewCreateCustomerUseCase(customerGateway)
-	findCustomerUseCase = usecase.NewFindCustomerUseCase(customerGateway)
-	listCustomersUseCase = usecase.NewListCustomersUseCase(customerGateway)
-	updateCustomerUseCase = usecase.NewUpdateCustomerUseCase(customerGateway)
-	deleteCustomersUseCase = usecase.NewDeleteCustomerUseCase(customerGateway)
-	createAccountUseCase = usecase.NewCreateAccountUseCase(accountGateway, customerGateway)
-	listCustomerAccountsUseCase = usecase.NewListCustomerAccountsUseCase(accountGateway, customerGateway)
-	depositUseCase = usecase.NewDepositUseCase(accountGateway)
-	withdrawUseCase = usecase.NewWithdrawUseCase(accountGateway)
-	showAccountBalanceUseCase = usecase.NewShowAccountBalanceUseCase(accountGateway)
+func connectNats() (err error) {
+	natsConn, err = cnats.Connect(config.NatsURL)
+	if err != nil {
+		return err
+	}
+	jetStream, err = jetstream.New(natsConn)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return nats.EnsureStreams(ctx, jetStream, []string{"transactions", "balances", "payment_requests"})
 }
 
-func createHandlers() {
-	createCustomerHandler = webserver.NewCreateCustomerHandler(createCustomerUseCase)
-	findCustomerHandler = webserver.NewFindCustomerHandler(findCustomerUseCase)
-	listCustomersHandler = webserver.NewListCustomersHandler(listCustomersUseCase)
-	updateCustomerHandler = webserver.NewUpdateCustomerHandler(updateCustomerUseCase)
-	deleteCustomerHandler = webserver.NewDeleteCustomerHandler(deleteCustomersUseCase)
-	createAccountHandler = webserver.NewCreateAccountHandler(createAccountUseCase)
-	listCustomerAccountsHandler = webserver.NewListCustomerAccountsHandler(listCustomerAccountsUseCase)
-	depositHandler = webserver.NewDepositHandler(depositUseCase)
-	withdrawHandler = webserver.NewWithdrawHandler(withdrawUseCase)
-	showAccountBalanceHandler = webserver.NewShowAccountBalanceHandler(showAccountBalanceUseCase)
-	createTransactionHandler = webserver.NewCreateTransactionHandler(eventDispatcher)
+func startEventConsumer(ctx context.Context) (err error) {
+	if config.MessageBroker == "nats" {
+		subscriber, err = nats.NewSubscriber(ctx, jetStream, "wallet", []string{"balances"})
+	} else {
+		subscriber, err = newKafkaConsumer()
+	}
+	if err != nil {
+		return err
+	}
+	defer subscriber.Close()
+	return subscriber.Run(ctx, walletCore.HandleBalancesMessage)
 }
 
-func startServer() error {
-	server = webserver.NewServer(config.Port)
-	server.AddHandler(createCustomerHandler)
-	server.AddHandler(findCustomerHandler)
-	server.AddHandler(listCustomersHandler)
-	server.AddHandler(updateCustomerHandler)
-	server.AddHandler(deleteCustomerHandler)
-	server.AddHandler(createAccountHandler)
-	server.AddHandler(listCustomerAccountsHandler)
-	server.AddHandler(depositHandler)
-	server.AddHandler(withdrawHandler)
-	server.AddHandler(showAccountBalanceHandler)
-	server.AddHandler(createTransactionHandler)
-
-	ch := make(chan error)
-	go func() {
-		fmt.Printf("[Web Server] Starting on port %s\n", config.Port)
-		if err := server.Start(); err != nil {
-			ch <- err
-		}
-	}()
-	return <-ch
+func newKafkaConsumer() (messaging.Subscriber, error) {
+	configMap := ckafka.ConfigMap{
+		"bootstrap.servers": config.KafkaDSN,
+		"group.id":          "wallet",
+		"isolation.level":   "read_committed",
+	}
+	consumer, err := kafka.NewConsumer(&configMap, []string{"balances"}, kafka.WithRetryTopics(producer, kafka.DefaultRetryTiers...))
+	if err != nil {
+		return nil, err
+	}
+	return consumer, nil
+}
+
+func shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := walletCore.Shutdown(ctx); err != nil {
+		log.Println(err.Error())
+	}
+	if err := publisher.Close(ctx); err != nil {
+		log.Println(err.Error())
+	}
+	if natsConn != nil {
+		natsConn.Close()
+	}
 }
@@ -3,10 +3,17 @@ package configs
 import "github.com/spf13/viper"
 
 type Config struct {
-	Port            string `mapstructure:"PORT"`
-	WalletCoreDSN   string `mapstructure:"WALLET_CORE_DSN"`
-	TransactionsDSN string `mapstructure:"TRANSACTIONS_DSN"`
-	KafkaDSN        string `mapstructure:"KAFKA_DSN"`
+	Port                    string `mapstructure:"PORT"`
+	WalletCoreDSN           string `mapstructure:"WALLET_CORE_DSN"`
+	TransactionsDSN         string `mapstructure:"TRANSACTIONS_DSN"`
+	MessageBroker           string `mapstructure:"MESSAGE_BROKER"`
+	KafkaDSN                string `mapstructure:"KAFKA_DSN"`
+	KafkaCodecs             string `mapstructure:"KAFKA_CODECS"`
+	TopicsPath              string `mapstructure:"TOPICS_PATH"`
+	NatsURL                 string `mapstructure:"NATS_URL"`
+	SchemasPath             string `mapstructure:"SCHEMAS_PATH"`
+	AccountStore            string `mapstructure:"ACCOUNT_STORE"`
+	AccountSnapshotInterval int    `mapstructure:"ACCOUNT_SNAPSHOT_INTERVAL"`
 }
 
 func LoadConfig(path string) (config *Config, err error) {
@@ -7,30 +7,60 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	AccountOpened    = "account.opened"
+	MoneyDeposited   = "account.money_deposited"
+	MoneyWithdrawn   = "account.money_withdrawn"
+	TransferDebited  = "account.transfer_debited"
+	TransferCredited = "account.transfer_credited"
+)
+
+type AccountEvent struct {
+	Type       string    `json:"-"`
+	AccountId  string    `json:"accountId"`
+	CustomerId string    `json:"customerId,omitempty"`
+	Amount     float64   `json:"amount,omitempty"`
+	Reference  string    `json:"reference,omitempty"`
+	OccurredAt time.Time `json:"-"`
+}
+
 type Account struct {
 	Entity
 	Customer *Customer
 	Balance  float64
+	Version  int
+	changes  []AccountEvent
 }
 
 func NewAccount(customer *Customer) *Account {
-	return &Account{
-		Entity: Entity{
-			Id:        uuid.NewString(),
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		},
-		Balance:  0,
-		Customer: customer,
+	account := &Account{Customer: customer}
+	account.record(AccountEvent{
+		Type:       AccountOpened,
+		AccountId:  uuid.NewString(),
+		CustomerId: customer.Id,
+		OccurredAt: time.Now(),
+	})
+	return account
+}
+
+func RebuildAccount(snapshot *Account, history []AccountEvent) *Account {
+	account := &Account{}
+	if snapshot != nil {
+		*account = *snapshot
+		account.changes = nil
+	}
+	for _, event := range history {
+		account.apply(event)
+		account.Version++
 	}
+	return account
 }
 
 func (e *Account) Deposit(amount float64) error {
 	if amount < 0 {
 		return errors.New("unable to deposito: negative amount")
 	}
-	e.Balance += amount
-	e.UpdatedAt = time.Now()
+	e.record(AccountEvent{Type: MoneyDeposited, AccountId: e.Id, Amount: amount, OccurredAt: time.Now()})
 	return nil
 }
 
@@ -38,7 +68,52 @@ func (e *Account) Withdraw(amount float64) error {
 	if amount > e.Balance {
 		return errors.New("unable to withdraw: insufficient funds")
 	}
-	e.Balance -= amount
-	e.UpdatedAt = time.Now()
+	e.record(AccountEvent{Type: MoneyWithdrawn, AccountId: e.Id, Amount: amount, OccurredAt: time.Now()})
+	return nil
+}
+
+func (e *Account) Credit(amount float64, reference string) error {
+	if amount < 0 {
+		return errors.New("unable to credit: negative amount")
+	}
+	e.record(AccountEvent{Type: TransferCredited, AccountId: e.Id, Amount: amount, Reference: reference, OccurredAt: time.Now()})
+	return nil
+}
+
+func (e *Account) Debit(amount float64, reference string) error {
+	if amount > e.Balance {
+		return errors.New("unable to debit: insufficient funds")
+	}
+	e.record(AccountEvent{Type: TransferDebited, AccountId: e.Id, Amount: amount, Reference: reference, OccurredAt: time.Now()})
 	return nil
 }
+
+func (e *Account) Changes() []AccountEvent {
+	return e.changes
+}
+
+func (e *Account) MarkChangesCommitted() {
+	e.Version += len(e.changes)
+	e.changes = nil
+}
+
+func (e *Account) record(event AccountEvent) {
+	e.apply(event)
+	e.changes = append(e.changes, event)
+}
+
+func (e *Account) apply(event AccountEvent) {
+	switch event.Type {
+	case AccountOpened:
+		e.Id = event.AccountId
+		e.CreatedAt = event.OccurredAt
+		if e.Customer == nil || e.Customer.Id != event.CustomerId {
+			e.Customer = &Customer{Entity: Entity{Id: event.CustomerId}}
+		}
+	case MoneyDeposited, TransferCredited:
+		e.Balance += event.Amount
+	case MoneyWithdrawn, TransferDebited:
+		e.Balance -= event.Amount
+	}
+	e.UpdatedAt = event.OccurredAt
+}
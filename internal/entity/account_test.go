@@ -63,6 +63,58 @@ func (suite *AccountTestSuite) TestWithdraw_WithInsufficientFunds() {
 	assert.EqualError(suite.T(), err, "unable to withdraw: insufficient funds")
 }
 
+func (suite *AccountTestSuite) TestAccount_Changes() {
+	account := NewAccount(suite.customer)
+	assert.Nil(suite.T(), account.Deposit(100))
+	assert.Nil(suite.T(), account.Withdraw(30))
+	assert.Nil(suite.T(), account.Credit(20, "transfer-1"))
+	assert.Nil(suite.T(), account.Debit(40, "transfer-2"))
+
+	changes := account.Changes()
+	assert.Len(suite.T(), changes, 5)
+	types := []string{}
+	for _, change := range changes {
+		assert.Equal(suite.T(), account.Id, change.AccountId)
+		types = append(types, change.Type)
+	}
+	assert.Equal(suite.T(), []string{AccountOpened, MoneyDeposited, MoneyWithdrawn, TransferCredited, TransferDebited}, types)
+	assert.Equal(suite.T(), suite.customer.Id, changes[0].CustomerId)
+	assert.Equal(suite.T(), "transfer-2", changes[4].Reference)
+	assert.Equal(suite.T(), 50.0, account.Balance)
+
+	account.MarkChangesCommitted()
+	assert.Empty(suite.T(), account.Changes())
+	assert.Equal(suite.T(), 5, account.Version)
+}
+
+func (suite *AccountTestSuite) TestAccount_Debit_WithInsufficientFunds() {
+	account := NewAccount(suite.customer)
+	err := account.Debit(10, "transfer-1")
+	assert.EqualError(suite.T(), err, "unable to debit: insufficient funds")
+	assert.Len(suite.T(), account.Changes(), 1)
+}
+
+func (suite *AccountTestSuite) TestRebuildAccount() {
+	account := NewAccount(suite.customer)
+	account.Deposit(100)
+	account.Debit(25, "transfer-1")
+	history := account.Changes()
+
+	rebuilt := RebuildAccount(nil, history)
+	assert.Equal(suite.T(), account.Id, rebuilt.Id)
+	assert.Equal(suite.T(), suite.customer.Id, rebuilt.Customer.Id)
+	assert.Equal(suite.T(), 75.0, rebuilt.Balance)
+	assert.Equal(suite.T(), 3, rebuilt.Version)
+	assert.Equal(suite.T(), account.CreatedAt, rebuilt.CreatedAt)
+	assert.Empty(suite.T(), rebuilt.Changes())
+
+	snapshot := RebuildAccount(nil, history[:2])
+	rebuilt = RebuildAccount(snapshot, history[2:])
+	assert.Equal(suite.T(), 75.0, rebuilt.Balance)
+	assert.Equal(suite.T(), 3, rebuilt.Version)
+	assert.Equal(suite.T(), 100.0, snapshot.Balance)
+}
+
 func TestAccountTestSuite(t *testing.T) {
 	suite.Run(t, new(AccountTestSuite))
 }
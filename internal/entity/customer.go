@@ -13,6 +13,8 @@ type Customer struct {
 	Entity
 	Name  string
 	Email string
+	Phone string
+	TaxId string
 }
 
 func NewCustomer(name, email string) (*Customer, error) {
@@ -39,6 +41,12 @@ func (e *Customer) IsValid() error {
 	if _, err := mail.ParseAddress(e.Email); err != nil {
 		errs = append(errs, errors.New("invalid email address"))
 	}
+	if e.Phone != "" && !phoneRegexp.MatchString(e.Phone) {
+		errs = append(errs, errors.New("invalid phone number"))
+	}
+	if e.TaxId != "" && !taxIdRegexp.MatchString(e.TaxId) {
+		errs = append(errs, errors.New("invalid tax id"))
+	}
 	if len(errs) > 0 {
 		return errors.Join(errs...)
 	}
@@ -54,3 +62,28 @@ func (e *Customer) Update(name, email string) error {
 	}
 	return nil
 }
+
+func (e *Customer) UpdateDocuments(phone, taxId string) error {
+	e.Phone = ""
+	if strings.TrimSpace(phone) != "" {
+		e.Phone = NormalizeKeyValue(PhoneKey, phone)
+	}
+	e.TaxId = ""
+	if strings.TrimSpace(taxId) != "" {
+		e.TaxId = NormalizeKeyValue(TaxIdKey, taxId)
+	}
+	e.UpdatedAt = time.Now()
+	return e.IsValid()
+}
+
+func (e *Customer) Owns(keyType KeyType, value string) bool {
+	switch keyType {
+	case EmailKey:
+		return value == NormalizeKeyValue(EmailKey, e.Email)
+	case PhoneKey:
+		return e.Phone != "" && value == e.Phone
+	case TaxIdKey:
+		return e.TaxId != "" && value == e.TaxId
+	}
+	return true
+}
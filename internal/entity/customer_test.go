@@ -79,3 +79,19 @@ func TestCustomer_Update_WithEmptyNameAndInvalidEmail(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.EqualError(t, err, "name is required\ninvalid email address")
 }
+
+func TestCustomer_UpdateDocuments(t *testing.T) {
+	customer, _ := NewCustomer("Josimar Zimermann", "josimarz@yahoo.com.br")
+
+	err := customer.UpdateDocuments("+55 47 99999-0000", "123.456.789-09")
+	assert.Nil(t, err)
+	assert.Equal(t, "+5547999990000", customer.Phone)
+	assert.Equal(t, "12345678909", customer.TaxId)
+	assert.True(t, customer.Owns(PhoneKey, "+5547999990000"))
+	assert.True(t, customer.Owns(TaxIdKey, "12345678909"))
+	assert.False(t, customer.Owns(TaxIdKey, "98765432100"))
+
+	err = customer.UpdateDocuments("", "123")
+	assert.EqualError(t, err, "invalid tax id")
+	assert.False(t, customer.Owns(PhoneKey, "+5547999990000"))
+}
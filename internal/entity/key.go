@@ -0,0 +1,131 @@
+package entity
+
+import (
+	"errors"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type KeyType string
+
+const (
+	EmailKey  KeyType = "email"
+	PhoneKey  KeyType = "phone"
+	TaxIdKey  KeyType = "taxId"
+	RandomKey KeyType = "random"
+)
+
+var (
+	phoneRegexp = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+	taxIdRegexp = regexp.MustCompile(`^([0-9]{11}|[0-9]{14})$`)
+)
+
+type Key struct {
+	Entity
+	Type    KeyType
+	Value   string
+	Account *Account
+}
+
+func NewKey(keyType KeyType, value string, account *Account) (*Key, error) {
+	if keyType == RandomKey {
+		value = uuid.NewString()
+	}
+	key := &Key{
+		Entity: Entity{
+			Id:        uuid.NewString(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		Type:    keyType,
+		Value:   NormalizeKeyValue(keyType, value),
+		Account: account,
+	}
+	if err := key.IsValid(); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func NormalizeKeyValue(keyType KeyType, value string) string {
+	value = strings.TrimSpace(value)
+	switch keyType {
+	case EmailKey, RandomKey:
+		return strings.ToLower(value)
+	case PhoneKey:
+		return "+" + onlyDigits(value)
+	case TaxIdKey:
+		return onlyDigits(value)
+	}
+	return value
+}
+
+func ParseKeyValue(value string) (KeyType, string, error) {
+	value = strings.TrimSpace(value)
+	var keyType KeyType
+	switch {
+	case strings.Contains(value, "@"):
+		keyType = EmailKey
+	case strings.HasPrefix(value, "+"):
+		keyType = PhoneKey
+	case isUUID(value):
+		keyType = RandomKey
+	default:
+		keyType = TaxIdKey
+	}
+	key := &Key{Type: keyType, Value: NormalizeKeyValue(keyType, value), Account: &Account{}}
+	if err := key.IsValid(); err != nil {
+		return "", "", err
+	}
+	return key.Type, key.Value, nil
+}
+
+func (e *Key) IsValid() error {
+	if e.Account == nil {
+		return errors.New("account is required")
+	}
+	switch e.Type {
+	case EmailKey:
+		if _, err := mail.ParseAddress(e.Value); err != nil {
+			return errors.New("invalid email key")
+		}
+	case PhoneKey:
+		if !phoneRegexp.MatchString(e.Value) {
+			return errors.New("invalid phone key")
+		}
+	case TaxIdKey:
+		if !taxIdRegexp.MatchString(e.Value) {
+			return errors.New("invalid tax id key")
+		}
+	case RandomKey:
+		if !isUUID(e.Value) {
+			return errors.New("invalid random key")
+		}
+	default:
+		return errors.New("invalid key type")
+	}
+	return nil
+}
+
+func (e *Key) IsOwnedBy(customer *Customer) bool {
+	return e.Account.Customer != nil && customer != nil && e.Account.Customer.Id == customer.Id
+}
+
+func isUUID(value string) bool {
+	_, err := uuid.Parse(value)
+	return err == nil
+}
+
+func onlyDigits(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
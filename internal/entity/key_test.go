@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type KeyTestSuite struct {
+	suite.Suite
+	customer *Customer
+	account  *Account
+}
+
+func (suite *KeyTestSuite) SetupTest() {
+	customer, _ := NewCustomer("Josimar Zimermann", "josimarz@yahoo.com.br")
+	suite.customer = customer
+	suite.account = NewAccount(customer)
+}
+
+func (suite *KeyTestSuite) TestNewKey_WithEmail() {
+	key, err := NewKey(EmailKey, " JosimarZ@Yahoo.com.br ", suite.account)
+	assert.Nil(suite.T(), err)
+	assert.NotNil(suite.T(), key)
+	assert.Equal(suite.T(), "josimarz@yahoo.com.br", key.Value)
+	assert.Equal(suite.T(), suite.account, key.Account)
+}
+
+func (suite *KeyTestSuite) TestNewKey_WithInvalidEmail() {
+	key, err := NewKey(EmailKey, "josimarz.yahoo.com.br", suite.account)
+	assert.Nil(suite.T(), key)
+	assert.EqualError(suite.T(), err, "invalid email key")
+}
+
+func (suite *KeyTestSuite) TestNewKey_WithPhone() {
+	key, err := NewKey(PhoneKey, "+55 (47) 99999-0000", suite.account)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "+5547999990000", key.Value)
+}
+
+func (suite *KeyTestSuite) TestNewKey_WithInvalidPhone() {
+	key, err := NewKey(PhoneKey, "123", suite.account)
+	assert.Nil(suite.T(), key)
+	assert.EqualError(suite.T(), err, "invalid phone key")
+}
+
+func (suite *KeyTestSuite) TestNewKey_WithTaxId() {
+	key, err := NewKey(TaxIdKey, "123.456.789-09", suite.account)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "12345678909", key.Value)
+}
+
+func (suite *KeyTestSuite) TestNewKey_WithInvalidTaxId() {
+	key, err := NewKey(TaxIdKey, "123.456", suite.account)
+	assert.Nil(suite.T(), key)
+	assert.EqualError(suite.T(), err, "invalid tax id key")
+}
+
+func (suite *KeyTestSuite) TestNewKey_WithRandom() {
+	key, err := NewKey(RandomKey, "ignored", suite.account)
+	assert.Nil(suite.T(), err)
+	_, err = uuid.Parse(key.Value)
+	assert.Nil(suite.T(), err)
+}
+
+func (suite *KeyTestSuite) TestNewKey_WithInvalidType() {
+	key, err := NewKey("iban", "DE89370400440532013000", suite.account)
+	assert.Nil(suite.T(), key)
+	assert.EqualError(suite.T(), err, "invalid key type")
+}
+
+func (suite *KeyTestSuite) TestNewKey_WithoutAccount() {
+	key, err := NewKey(EmailKey, "josimarz@yahoo.com.br", nil)
+	assert.Nil(suite.T(), key)
+	assert.EqualError(suite.T(), err, "account is required")
+}
+
+func (suite *KeyTestSuite) TestKey_IsOwnedBy() {
+	key, err := NewKey(EmailKey, "josimarz@yahoo.com.br", suite.account)
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), key.IsOwnedBy(suite.customer))
+
+	other, _ := NewCustomer("Gustavo Kuerten", "guga@tennis.com")
+	assert.False(suite.T(), key.IsOwnedBy(other))
+}
+
+func (suite *KeyTestSuite) TestParseKeyValue() {
+	random := uuid.NewString()
+	cases := []struct {
+		value    string
+		keyType  KeyType
+		expected string
+	}{
+		{"JosimarZ@yahoo.com.br", EmailKey, "josimarz@yahoo.com.br"},
+		{"+55 47 99999-0000", PhoneKey, "+5547999990000"},
+		{"123.456.789-09", TaxIdKey, "12345678909"},
+		{random, RandomKey, random},
+	}
+	for _, c := range cases {
+		keyType, value, err := ParseKeyValue(c.value)
+		assert.Nil(suite.T(), err)
+		assert.Equal(suite.T(), c.keyType, keyType)
+		assert.Equal(suite.T(), c.expected, value)
+	}
+}
+
+func (suite *KeyTestSuite) TestParseKeyValue_WithInvalidValue() {
+	_, _, err := ParseKeyValue("josimarz")
+	assert.EqualError(suite.T(), err, "invalid tax id key")
+}
+
+func TestKeyTestSuite(t *testing.T) {
+	suite.Run(t, new(KeyTestSuite))
+}
@@ -0,0 +1,119 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PaymentRequestStatus string
+
+const (
+	PaymentRequestPending  PaymentRequestStatus = "pending"
+	PaymentRequestAccepted PaymentRequestStatus = "accepted"
+	PaymentRequestDeclined PaymentRequestStatus = "declined"
+	PaymentRequestExpired  PaymentRequestStatus = "expired"
+)
+
+type PaymentRequest struct {
+	Entity
+	Requester *Account
+	Payer     *Account
+	Amount    float64
+	Memo      string
+	Status    PaymentRequestStatus
+	ExpiresAt time.Time
+}
+
+func NewPaymentRequest(requester, payer *Account, amount float64, memo string, expiresAt time.Time) (*PaymentRequest, error) {
+	request := &PaymentRequest{
+		Entity: Entity{
+			Id:        uuid.NewString(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		Requester: requester,
+		Payer:     payer,
+		Amount:    amount,
+		Memo:      memo,
+		Status:    PaymentRequestPending,
+		ExpiresAt: expiresAt,
+	}
+	if err := request.IsValid(); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func (e *PaymentRequest) IsValid() error {
+	if e.Requester == nil || e.Payer == nil {
+		return errors.New("requester and payer accounts are required")
+	}
+	if e.Requester.Id == e.Payer.Id {
+		return errors.New("requester and payer accounts must be different")
+	}
+	if e.Amount <= 0 {
+		return errors.New("amount must be greater than zero")
+	}
+	if !e.ExpiresAt.After(e.CreatedAt) {
+		return errors.New("expiration must be in the future")
+	}
+	return nil
+}
+
+func (e *PaymentRequest) IsExpired(now time.Time) bool {
+	return !now.Before(e.ExpiresAt)
+}
+
+func (e *PaymentRequest) Accept(now time.Time) error {
+	if err := e.checkPending(now); err != nil {
+		return err
+	}
+	e.transition(PaymentRequestAccepted, now)
+	return nil
+}
+
+func (e *PaymentRequest) Decline(now time.Time) error {
+	if err := e.checkPending(now); err != nil {
+		return err
+	}
+	e.transition(PaymentRequestDeclined, now)
+	return nil
+}
+
+func (e *PaymentRequest) Expire(now time.Time) error {
+	if e.Status != PaymentRequestPending {
+		return errors.New("payment request is not pending")
+	}
+	if !e.IsExpired(now) {
+		return errors.New("payment request has not expired yet")
+	}
+	e.transition(PaymentRequestExpired, now)
+	return nil
+}
+
+// Reopen moves an accepted request back to pending when the transfer it
+// should have started could not be emitted.
+func (e *PaymentRequest) Reopen(now time.Time) error {
+	if e.Status != PaymentRequestAccepted {
+		return errors.New("payment request is not accepted")
+	}
+	e.transition(PaymentRequestPending, now)
+	return nil
+}
+
+func (e *PaymentRequest) checkPending(now time.Time) error {
+	if e.Status != PaymentRequestPending {
+		return errors.New("payment request is not pending")
+	}
+	if e.IsExpired(now) {
+		return errors.New("payment request has expired")
+	}
+	return nil
+}
+
+func (e *PaymentRequest) transition(status PaymentRequestStatus, now time.Time) {
+	e.Status = status
+	e.UpdatedAt = now
+}
@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type PaymentRequestTestSuite struct {
+	suite.Suite
+	requester *Account
+	payer     *Account
+}
+
+func (suite *PaymentRequestTestSuite) SetupTest() {
+	customer, _ := NewCustomer("Ana Ivanovic", "ivanovic@wta.com")
+	suite.requester = NewAccount(customer)
+
+	customer, _ = NewCustomer("Maria Sharapova", "sharapova@wta.com")
+	suite.payer = NewAccount(customer)
+}
+
+func (suite *PaymentRequestTestSuite) TestNewPaymentRequest() {
+	expiresAt := time.Now().Add(time.Hour)
+	request, err := NewPaymentRequest(suite.requester, suite.payer, 150.5, "dinner", expiresAt)
+	assert.Nil(suite.T(), err)
+	assert.NotNil(suite.T(), request)
+	assert.Equal(suite.T(), PaymentRequestPending, request.Status)
+	assert.Equal(suite.T(), 150.5, request.Amount)
+	assert.Equal(suite.T(), "dinner", request.Memo)
+	assert.Equal(suite.T(), expiresAt, request.ExpiresAt)
+}
+
+func (suite *PaymentRequestTestSuite) TestNewPaymentRequest_WithSameAccount() {
+	request, err := NewPaymentRequest(suite.payer, suite.payer, 150.5, "", time.Now().Add(time.Hour))
+	assert.Nil(suite.T(), request)
+	assert.EqualError(suite.T(), err, "requester and payer accounts must be different")
+}
+
+func (suite *PaymentRequestTestSuite) TestNewPaymentRequest_WithInvalidAmount() {
+	request, err := NewPaymentRequest(suite.requester, suite.payer, 0, "", time.Now().Add(time.Hour))
+	assert.Nil(suite.T(), request)
+	assert.EqualError(suite.T(), err, "amount must be greater than zero")
+}
+
+func (suite *PaymentRequestTestSuite) TestNewPaymentRequest_WithPastExpiration() {
+	request, err := NewPaymentRequest(suite.requester, suite.payer, 10, "", time.Now().Add(-time.Hour))
+	assert.Nil(suite.T(), request)
+	assert.EqualError(suite.T(), err, "expiration must be in the future")
+}
+
+func (suite *PaymentRequestTestSuite) TestPaymentRequest_Accept() {
+	request, _ := NewPaymentRequest(suite.requester, suite.payer, 10, "", time.Now().Add(time.Hour))
+	err := request.Accept(time.Now())
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), PaymentRequestAccepted, request.Status)
+
+	err = request.Decline(time.Now())
+	assert.EqualError(suite.T(), err, "payment request is not pending")
+}
+
+func (suite *PaymentRequestTestSuite) TestPaymentRequest_Accept_WhenExpired() {
+	request, _ := NewPaymentRequest(suite.requester, suite.payer, 10, "", time.Now().Add(time.Hour))
+	err := request.Accept(time.Now().Add(2 * time.Hour))
+	assert.EqualError(suite.T(), err, "payment request has expired")
+	assert.Equal(suite.T(), PaymentRequestPending, request.Status)
+}
+
+func (suite *PaymentRequestTestSuite) TestPaymentRequest_Decline() {
+	request, _ := NewPaymentRequest(suite.requester, suite.payer, 10, "", time.Now().Add(time.Hour))
+	err := request.Decline(time.Now())
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), PaymentRequestDeclined, request.Status)
+}
+
+func (suite *PaymentRequestTestSuite) TestPaymentRequest_Expire() {
+	request, _ := NewPaymentRequest(suite.requester, suite.payer, 10, "", time.Now().Add(time.Hour))
+	err := request.Expire(time.Now())
+	assert.EqualError(suite.T(), err, "payment request has not expired yet")
+
+	err = request.Expire(time.Now().Add(time.Hour))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), PaymentRequestExpired, request.Status)
+}
+
+func (suite *PaymentRequestTestSuite) TestPaymentRequest_Reopen() {
+	request, _ := NewPaymentRequest(suite.requester, suite.payer, 50.0, "", time.Now().Add(time.Hour))
+	assert.Error(suite.T(), request.Reopen(time.Now()))
+	request.Accept(time.Now())
+	assert.Nil(suite.T(), request.Reopen(time.Now()))
+	assert.Equal(suite.T(), PaymentRequestPending, request.Status)
+}
+
+func TestPaymentRequestTestSuite(t *testing.T) {
+	suite.Run(t, new(PaymentRequestTestSuite))
+}
@@ -43,6 +43,6 @@ func (e *Transaction) IsValid() error {
 }
 
 func (e *Transaction) Commit() {
-	e.To.Deposit(e.Amount)
-	e.From.Withdraw(e.Amount)
+	e.To.Credit(e.Amount, e.Id)
+	e.From.Debit(e.Amount, e.Id)
 }
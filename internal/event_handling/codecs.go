@@ -0,0 +1,21 @@
+package eventhandling
+
+import (
+	"path/filepath"
+
+	"github.com/josimarz/fc-eda-challenge/internal/event_handling/pb"
+	"github.com/josimarz/fc-eda-challenge/pkg/events/codec"
+	"google.golang.org/protobuf/proto"
+)
+
+func NewCodecRegistry(schemasPath string) (*codec.Registry, error) {
+	avro, err := codec.LoadAvro(filepath.Join(schemasPath, "avro"))
+	if err != nil {
+		return nil, err
+	}
+	protobuf := codec.NewProtobuf(map[string]proto.Message{
+		codec.SchemaKey(TransactionCreated, 1): &pb.TransactionCreated{},
+		codec.SchemaKey(BalancesUpdated, 1):    &pb.BalancesUpdated{},
+	})
+	return codec.NewRegistry(codec.NewJSON(), protobuf, avro), nil
+}
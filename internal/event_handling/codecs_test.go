@@ -0,0 +1,51 @@
+package eventhandling
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/josimarz/fc-eda-challenge/pkg/events/codec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCodecRegistry(t *testing.T) {
+	registry, err := NewCodecRegistry("../../schemas")
+	assert.Nil(t, err)
+
+	now := time.Date(2023, 10, 19, 12, 30, 0, 0, time.FixedZone("BRT", -3*60*60))
+	transactionCreated := TransactionCreatedPayload{From: "1", To: "2", Amount: 10}
+	balancesUpdated := BalancesUpdatedPayload{
+		From:   AccountBalance{Id: "1", Balance: 90, CreatedAt: now, UpdatedAt: now},
+		To:     AccountBalance{Id: "2", Balance: 110, CreatedAt: now, UpdatedAt: now},
+		Amount: 10,
+	}
+
+	for _, contentType := range []string{"application/json", "application/protobuf", "application/avro"} {
+		c, err := registry.Get(contentType)
+		assert.Nil(t, err)
+
+		envelope := roundTrip(t, c, NewTransactionCreatedEvent(transactionCreated))
+		var decodedTransactionCreated TransactionCreatedPayload
+		assert.Nil(t, envelope.Decode(&decodedTransactionCreated))
+		assert.Equal(t, transactionCreated, decodedTransactionCreated, contentType)
+
+		envelope = roundTrip(t, c, NewBalancesUpdatedEvent(balancesUpdated))
+		var decodedBalancesUpdated BalancesUpdatedPayload
+		assert.Nil(t, envelope.Decode(&decodedBalancesUpdated))
+		assert.Equal(t, balancesUpdated.Amount, decodedBalancesUpdated.Amount, contentType)
+		assert.Equal(t, balancesUpdated.To.Balance, decodedBalancesUpdated.To.Balance, contentType)
+		assert.True(t, now.Equal(decodedBalancesUpdated.From.CreatedAt), contentType)
+	}
+}
+
+func roundTrip(t *testing.T, c codec.Codec, event events.Event) *events.Envelope {
+	envelope, err := events.NewEnvelope(context.Background(), "test", event)
+	assert.Nil(t, err)
+	encoded, err := c.Encode(envelope)
+	assert.Nil(t, err)
+	decoded := &events.Envelope{Type: envelope.Type, SchemaVersion: envelope.SchemaVersion}
+	assert.Nil(t, c.Decode(encoded, decoded))
+	return decoded
+}
@@ -1,61 +1,83 @@
 package eventhandling
 
-import "time"
+import (
+	"time"
 
-type TransactionCreatedEvent struct {
-	name     string
-	dateTime time.Time
-	payload  interface{}
-}
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+)
 
-func NewTransactionCreatedEvent() *TransactionCreatedEvent {
-	return &TransactionCreatedEvent{
-		name:     "transaction.created",
-		dateTime: time.Now(),
-	}
-}
+const (
+	TransactionCreated     = "transaction.created"
+	BalancesUpdated        = "balances.updated"
+	PaymentRequestCreated  = "payment_request.created"
+	PaymentRequestAccepted = "payment_request.accepted"
+	PaymentRequestDeclined = "payment_request.declined"
+	PaymentRequestExpired  = "payment_request.expired"
+)
+
+const (
+	TransactionCreatedVersion = 1
+	BalancesUpdatedVersion    = 1
+	PaymentRequestVersion     = 1
+)
 
-func (e *TransactionCreatedEvent) GetName() string {
-	return e.name
+type TransactionCreatedPayload struct {
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Amount float64 `json:"amount"`
 }
 
-func (e *TransactionCreatedEvent) GetPayload() interface{} {
-	return e.payload
+type AccountBalance struct {
+	Id        string    `json:"id"`
+	Balance   float64   `json:"balance"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
 }
 
-func (e *TransactionCreatedEvent) SetPayload(payload interface{}) {
-	e.payload = payload
+type BalancesUpdatedPayload struct {
+	From   AccountBalance `json:"from"`
+	To     AccountBalance `json:"to"`
+	Amount float64        `json:"amount"`
 }
 
-func (e *TransactionCreatedEvent) GetDateTime() time.Time {
-	return e.dateTime
+type PaymentRequestPayload struct {
+	Id          string    `json:"id"`
+	RequesterId string    `json:"requesterId"`
+	PayerId     string    `json:"payerId"`
+	Amount      float64   `json:"amount"`
+	Memo        string    `json:"memo"`
+	Status      string    `json:"status"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
 }
 
-type BalancesUpdatedEvent struct {
-	name     string
-	dateTime time.Time
-	payload  interface{}
+type TransactionCreatedEvent = events.TypedEvent[TransactionCreatedPayload]
+
+type BalancesUpdatedEvent = events.TypedEvent[BalancesUpdatedPayload]
+
+type PaymentRequestEvent = events.TypedEvent[PaymentRequestPayload]
+
+func NewTransactionCreatedEvent(payload TransactionCreatedPayload) *TransactionCreatedEvent {
+	return events.NewVersionedEvent(TransactionCreated, TransactionCreatedVersion, payload)
 }
 
-func NewBalancesUpdatedEvent() *BalancesUpdatedEvent {
-	return &BalancesUpdatedEvent{
-		name:     "balances.updated",
-		dateTime: time.Now(),
-	}
+func NewBalancesUpdatedEvent(payload BalancesUpdatedPayload) *BalancesUpdatedEvent {
+	return events.NewVersionedEvent(BalancesUpdated, BalancesUpdatedVersion, payload)
 }
 
-func (e *BalancesUpdatedEvent) GetName() string {
-	return e.name
+func NewPaymentRequestCreatedEvent(payload PaymentRequestPayload) *PaymentRequestEvent {
+	return events.NewVersionedEvent(PaymentRequestCreated, PaymentRequestVersion, payload)
 }
 
-func (e *BalancesUpdatedEvent) GetPayload() interface{} {
-	return e.payload
+func NewPaymentRequestAcceptedEvent(payload PaymentRequestPayload) *PaymentRequestEvent {
+	return events.NewVersionedEvent(PaymentRequestAccepted, PaymentRequestVersion, payload)
 }
 
-func (e *BalancesUpdatedEvent) SetPayload(payload interface{}) {
-	e.payload = payload
+func NewPaymentRequestDeclinedEvent(payload PaymentRequestPayload) *PaymentRequestEvent {
+	return events.NewVersionedEvent(PaymentRequestDeclined, PaymentRequestVersion, payload)
 }
 
-func (e *BalancesUpdatedEvent) GetDateTime() time.Time {
-	return e.dateTime
+func NewPaymentRequestExpiredEvent(payload PaymentRequestPayload) *PaymentRequestEvent {
+	return events.NewVersionedEvent(PaymentRequestExpired, PaymentRequestVersion, payload)
 }
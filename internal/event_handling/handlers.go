@@ -1,37 +1,88 @@
 package eventhandling
 
 import (
+	"context"
 	"fmt"
-	"sync"
+	"log"
+	"time"
 
-	"github.com/josimarz/fc-eda-challenge/internal/infra/kafka"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/messaging"
 	"github.com/josimarz/fc-eda-challenge/pkg/events"
 )
 
 type TransactionCreatedHandler struct {
-	producer *kafka.Producer
+	publisher messaging.Publisher
 }
 
-func NewTransactionCreatedHandler(producer *kafka.Producer) *TransactionCreatedHandler {
-	return &TransactionCreatedHandler{producer}
+func NewTransactionCreatedHandler(publisher messaging.Publisher) *TransactionCreatedHandler {
+	return &TransactionCreatedHandler{publisher}
 }
 
-func (h *TransactionCreatedHandler) Handle(message events.Event, wg *sync.WaitGroup) {
-	defer wg.Done()
-	h.producer.Publish(message.GetPayload(), nil, "transactions")
+func (h *TransactionCreatedHandler) Name() string {
+	return "TransactionCreatedHandler"
+}
+
+func (h *TransactionCreatedHandler) Handle(ctx context.Context, message *TransactionCreatedEvent) error {
+	if err := h.publisher.PublishSync(ctx, message, PartitionKey(message), "transactions"); err != nil {
+		return err
+	}
 	fmt.Println("TransactionCreatedHandler called")
+	return nil
 }
 
 type BalancesUpdatedHandler struct {
-	producer *kafka.Producer
+	publisher messaging.Publisher
 }
 
-func NewBalancesUpdatedHandler(producer *kafka.Producer) *BalancesUpdatedHandler {
-	return &BalancesUpdatedHandler{producer}
+func NewBalancesUpdatedHandler(publisher messaging.Publisher) *BalancesUpdatedHandler {
+	return &BalancesUpdatedHandler{publisher}
 }
 
-func (h *BalancesUpdatedHandler) Handle(message events.Event, wg *sync.WaitGroup) {
-	defer wg.Done()
-	h.producer.Publish(message.GetPayload(), nil, "balances")
+func (h *BalancesUpdatedHandler) Name() string {
+	return "BalancesUpdatedHandler"
+}
+
+func (h *BalancesUpdatedHandler) Handle(ctx context.Context, message *BalancesUpdatedEvent) error {
+	if err := h.publisher.PublishSync(ctx, message, PartitionKey(message), "balances"); err != nil {
+		return err
+	}
 	fmt.Println("BalancesUpdatedHandler called")
+	return nil
+}
+
+type PaymentRequestChangedHandler struct {
+	publisher messaging.Publisher
+}
+
+func NewPaymentRequestChangedHandler(publisher messaging.Publisher) *PaymentRequestChangedHandler {
+	return &PaymentRequestChangedHandler{publisher}
+}
+
+func (h *PaymentRequestChangedHandler) Name() string {
+	return "PaymentRequestChangedHandler"
+}
+
+func (h *PaymentRequestChangedHandler) Handle(ctx context.Context, message *PaymentRequestEvent) error {
+	if err := h.publisher.PublishSync(ctx, message, PartitionKey(message), "payment_requests"); err != nil {
+		return err
+	}
+	fmt.Printf("PaymentRequestChangedHandler called for %s\n", message.GetName())
+	return nil
+}
+
+type AuditHandler struct {
+	service string
+}
+
+func NewAuditHandler(service string) *AuditHandler {
+	return &AuditHandler{service}
+}
+
+func (h *AuditHandler) Name() string {
+	return "AuditHandler"
+}
+
+func (h *AuditHandler) Handle(ctx context.Context, message events.Event) error {
+	log.Printf("[%s] event %s dispatched at %s\n", h.service, message.GetName(), message.GetDateTime().Format(time.RFC3339))
+	return nil
 }
@@ -0,0 +1,91 @@
+package eventhandling_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/josimarz/fc-eda-challenge/internal/entity"
+	eventhandling "github.com/josimarz/fc-eda-challenge/internal/event_handling"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/messaging"
+	"github.com/josimarz/fc-eda-challenge/internal/usecase"
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/josimarz/fc-eda-challenge/pkg/events/codec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandlers_WithMemoryBroker(t *testing.T) {
+	broker := messaging.NewMemoryBroker(3)
+	codecs := codec.NewRegistry(codec.NewJSON())
+
+	walletCore := events.NewEventDispatcher()
+	events.Subscribe[eventhandling.TransactionCreatedPayload](walletCore, eventhandling.TransactionCreated, eventhandling.NewTransactionCreatedHandler(broker.Publisher("walletcore", nil)))
+
+	customer, _ := entity.NewCustomer("Gustavo Kuerten", "guga@tennis.com")
+	from := entity.NewAccount(customer)
+	from.Deposit(100.0)
+	customer, _ = entity.NewCustomer("Ana Ivanovic", "ivanovic@wta.com")
+	to := entity.NewAccount(customer)
+	accountGateway := &usecase.MockAccountGateway{}
+	accountGateway.On("FindById", from.Id).Return(from, nil)
+	accountGateway.On("FindById", to.Id).Return(to, nil)
+	transactionGateway := &usecase.MockTransactionGateway{}
+	transactionGateway.On("Create", mock.Anything).Return(nil)
+	transactions := events.NewEventDispatcher()
+	events.Subscribe[eventhandling.BalancesUpdatedPayload](transactions, eventhandling.BalancesUpdated, eventhandling.NewBalancesUpdatedHandler(broker.Publisher("transactions", nil)))
+	createTransactionUseCase := usecase.NewCreateTransactionUseCase(transactionGateway, accountGateway, transactions)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		broker.Subscribe("wallet", []string{"transactions"}).Run(ctx, func(ctx context.Context, message *messaging.Message) error {
+			envelope, err := messaging.DecodeMessage(message, codecs)
+			if err != nil {
+				return events.Permanent(err)
+			}
+			input := usecase.CreateTransactionInput{Id: envelope.Id}
+			if err := envelope.Decode(&input); err != nil {
+				return events.Permanent(err)
+			}
+			_, err = createTransactionUseCase.Execute(ctx, &input)
+			return err
+		})
+	}()
+	var balances eventhandling.BalancesUpdatedPayload
+	var received *messaging.Headers
+	go func() {
+		defer wg.Done()
+		broker.Subscribe("walletcore", []string{"balances"}).Run(ctx, func(ctx context.Context, message *messaging.Message) error {
+			envelope, err := messaging.DecodeMessage(message, codecs)
+			if err != nil {
+				return events.Permanent(err)
+			}
+			received, _ = messaging.HeadersFrom(ctx)
+			defer cancel()
+			return envelope.Decode(&balances)
+		})
+	}()
+
+	trace := events.NewTraceContext()
+	event := eventhandling.NewTransactionCreatedEvent(eventhandling.TransactionCreatedPayload{From: from.Id, To: to.Id, Amount: 30})
+	assert.Nil(t, walletCore.Dispatch(events.WithTraceContext(context.Background(), trace), event))
+	wg.Wait()
+
+	assert.Equal(t, 70.0, balances.From.Balance)
+	assert.Equal(t, 30.0, balances.To.Balance)
+	assert.Equal(t, eventhandling.BalancesUpdated, received.EventType)
+	assert.Equal(t, event.GetId(), received.CorrelationId)
+	assert.Equal(t, trace.TraceId, received.Trace.TraceId)
+	for _, topic := range []string{"transactions", "balances"} {
+		messages := broker.Messages(topic)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, []byte(from.Id), messages[0].Key)
+	}
+	assert.Equal(t, int64(0), broker.Lag("wallet", "transactions"))
+	assert.Equal(t, int64(0), broker.Lag("walletcore", "balances"))
+}
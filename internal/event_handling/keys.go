@@ -0,0 +1,49 @@
+package eventhandling
+
+import (
+	"encoding/json"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+)
+
+type KeyFunc func(event events.Event) []byte
+
+var partitionKeys = map[string]KeyFunc{
+	TransactionCreated:     keyBy(func(payload TransactionCreatedPayload) string { return payload.From }),
+	BalancesUpdated:        keyBy(func(payload BalancesUpdatedPayload) string { return payload.From.Id }),
+	PaymentRequestCreated:  paymentRequestKey,
+	PaymentRequestAccepted: paymentRequestKey,
+	PaymentRequestDeclined: paymentRequestKey,
+	PaymentRequestExpired:  paymentRequestKey,
+}
+
+var paymentRequestKey = keyBy(func(payload PaymentRequestPayload) string { return payload.Id })
+
+func PartitionKey(event events.Event) []byte {
+	if key, ok := partitionKeys[event.GetName()]; ok {
+		return key(event)
+	}
+	return nil
+}
+
+func keyBy[T any](key func(payload T) string) KeyFunc {
+	return func(event events.Event) []byte {
+		var payload T
+		switch p := event.GetPayload().(type) {
+		case T:
+			payload = p
+		case *T:
+			payload = *p
+		case json.RawMessage:
+			if err := json.Unmarshal(p, &payload); err != nil {
+				return nil
+			}
+		default:
+			return nil
+		}
+		if id := key(payload); id != "" {
+			return []byte(id)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,26 @@
+package eventhandling
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartitionKey(t *testing.T) {
+	transactionCreated := NewTransactionCreatedEvent(TransactionCreatedPayload{From: "1", To: "2", Amount: 10})
+	assert.Equal(t, []byte("1"), PartitionKey(transactionCreated))
+
+	balancesUpdated := NewBalancesUpdatedEvent(BalancesUpdatedPayload{From: AccountBalance{Id: "3"}, To: AccountBalance{Id: "4"}})
+	assert.Equal(t, []byte("3"), PartitionKey(balancesUpdated))
+
+	paymentRequest := NewPaymentRequestCreatedEvent(PaymentRequestPayload{Id: "5"})
+	assert.Equal(t, []byte("5"), PartitionKey(paymentRequest))
+
+	replayed := events.NewTypedEvent[any](TransactionCreated, json.RawMessage(`{"from":"6","to":"7","amount":10}`))
+	assert.Equal(t, []byte("6"), PartitionKey(replayed))
+
+	assert.Nil(t, PartitionKey(events.NewTypedEvent("account.created", TransactionCreatedPayload{From: "1"})))
+	assert.Nil(t, PartitionKey(NewTransactionCreatedEvent(TransactionCreatedPayload{})))
+}
@@ -0,0 +1,3 @@
+package pb
+
+//go:generate protoc -I ../../../schemas/proto --go_out=../../.. --go_opt=module=github.com/josimarz/fc-eda-challenge wallet/events/v1/events.proto
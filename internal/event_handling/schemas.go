@@ -0,0 +1,14 @@
+package eventhandling
+
+import "github.com/josimarz/fc-eda-challenge/pkg/events"
+
+func NewSchemaRegistry() *events.SchemaRegistry {
+	registry := events.NewSchemaRegistry()
+	registry.Register(TransactionCreated, TransactionCreatedVersion)
+	registry.Register(BalancesUpdated, BalancesUpdatedVersion)
+	registry.Register(PaymentRequestCreated, PaymentRequestVersion)
+	registry.Register(PaymentRequestAccepted, PaymentRequestVersion)
+	registry.Register(PaymentRequestDeclined, PaymentRequestVersion)
+	registry.Register(PaymentRequestExpired, PaymentRequestVersion)
+	return registry
+}
@@ -1,10 +1,14 @@
 package gateway
 
-import "github.com/josimarz/fc-eda-challenge/internal/entity"
+import (
+	"context"
+
+	"github.com/josimarz/fc-eda-challenge/internal/entity"
+)
 
 type AccountGateway interface {
-	Create(account *entity.Account) error
-	FindById(id string) (*entity.Account, error)
-	FindByCustomer(customer *entity.Customer) ([]*entity.Account, error)
-	Update(account *entity.Account) error
+	Create(ctx context.Context, account *entity.Account) error
+	FindById(ctx context.Context, id string) (*entity.Account, error)
+	FindByCustomer(ctx context.Context, customer *entity.Customer) ([]*entity.Account, error)
+	Update(ctx context.Context, account *entity.Account) error
 }
@@ -1,11 +1,15 @@
 package gateway
 
-import "github.com/josimarz/fc-eda-challenge/internal/entity"
+import (
+	"context"
+
+	"github.com/josimarz/fc-eda-challenge/internal/entity"
+)
 
 type CustomerGateway interface {
-	Create(customer *entity.Customer) error
-	FindById(id string) (*entity.Customer, error)
-	FindAll() ([]*entity.Customer, error)
-	Update(customer *entity.Customer) error
-	Delete(customer *entity.Customer) error
+	Create(ctx context.Context, customer *entity.Customer) error
+	FindById(ctx context.Context, id string) (*entity.Customer, error)
+	FindAll(ctx context.Context) ([]*entity.Customer, error)
+	Update(ctx context.Context, customer *entity.Customer) error
+	Delete(ctx context.Context, customer *entity.Customer) error
 }
@@ -0,0 +1,8 @@
+package gateway
+
+import "errors"
+
+var (
+	ErrConflict = errors.New("resource was modified concurrently")
+	ErrNotFound = errors.New("resource not found")
+)
@@ -0,0 +1,14 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/josimarz/fc-eda-challenge/internal/entity"
+)
+
+type KeyGateway interface {
+	Create(ctx context.Context, key *entity.Key) error
+	FindByValue(ctx context.Context, value string) (*entity.Key, error)
+	FindByCustomer(ctx context.Context, customer *entity.Customer) ([]*entity.Key, error)
+	Delete(ctx context.Context, key *entity.Key) error
+}
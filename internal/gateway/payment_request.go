@@ -0,0 +1,17 @@
+package gateway
+
+import (
+	"context"
+	"time"
+
+	"github.com/josimarz/fc-eda-challenge/internal/entity"
+)
+
+type PaymentRequestGateway interface {
+	Create(ctx context.Context, request *entity.PaymentRequest) error
+	FindById(ctx context.Context, id string) (*entity.PaymentRequest, error)
+	FindByPayerCustomer(ctx context.Context, customer *entity.Customer, status entity.PaymentRequestStatus) ([]*entity.PaymentRequest, error)
+	FindExpired(ctx context.Context, now time.Time) ([]*entity.PaymentRequest, error)
+	Update(ctx context.Context, request *entity.PaymentRequest) error
+	Reopen(ctx context.Context, request *entity.PaymentRequest) error
+}
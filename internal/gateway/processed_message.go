@@ -0,0 +1,7 @@
+package gateway
+
+import "context"
+
+type ProcessedMessageGateway interface {
+	MarkProcessed(ctx context.Context, id string) (bool, error)
+}
@@ -1,7 +1,11 @@
 package gateway
 
-import "github.com/josimarz/fc-eda-challenge/internal/entity"
+import (
+	"context"
+
+	"github.com/josimarz/fc-eda-challenge/internal/entity"
+)
 
 type TransactionGateway interface {
-	Create(transaction *entity.Transaction) error
+	Create(ctx context.Context, transaction *entity.Transaction) error
 }
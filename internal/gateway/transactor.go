@@ -0,0 +1,7 @@
+package gateway
+
+import "context"
+
+type Transactor interface {
+	Transact(ctx context.Context, fn func(ctx context.Context) error) error
+}
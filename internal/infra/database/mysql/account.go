@@ -1,9 +1,12 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/josimarz/fc-eda-challenge/internal/entity"
+	"github.com/josimarz/fc-eda-challenge/internal/gateway"
 )
 
 type AccountGateway struct {
@@ -14,30 +17,20 @@ func NewAccountGateway(db *sql.DB) *AccountGateway {
 	return &AccountGateway{db}
 }
 
-func (g *AccountGateway) Create(account *entity.Account) error {
-	stmt, err := g.db.Prepare("insert into `account` (id, customer_id, balance, created_at, updated_at) values (?, ?, ?, ?, ?)")
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
-	args := []any{
-		account.Id,
-		account.Customer.Id,
-		account.Balance,
-		account.CreatedAt,
-		account.UpdatedAt,
-	}
-	if _, err := stmt.Exec(args...); err != nil {
+func (g *AccountGateway) Create(ctx context.Context, account *entity.Account) error {
+	if err := insertAccount(ctx, g.db, account); err != nil {
 		return err
 	}
+	account.MarkChangesCommitted()
 	return nil
 }
 
-func (g *AccountGateway) FindById(id string) (*entity.Account, error) {
-	stmt, err := g.db.Prepare(`
+func (g *AccountGateway) FindById(ctx context.Context, id string) (*entity.Account, error) {
+	stmt, err := connFrom(ctx, g.db).PrepareContext(ctx, `
 		select
 			a.id,
 			a.balance,
+			a.version,
 			a.created_at,
 			a.updated_at,
 			c.id,
@@ -59,6 +52,7 @@ func (g *AccountGateway) FindById(id string) (*entity.Account, error) {
 	dest := []any{
 		&account.Id,
 		&account.Balance,
+		&account.Version,
 		&account.CreatedAt,
 		&account.UpdatedAt,
 		&customer.Id,
@@ -67,20 +61,20 @@ func (g *AccountGateway) FindById(id string) (*entity.Account, error) {
 		&customer.CreatedAt,
 		&customer.UpdatedAt,
 	}
-	if err := stmt.QueryRow(id).Scan(dest...); err != nil {
+	if err := stmt.QueryRowContext(ctx, id).Scan(dest...); err != nil {
 		return nil, err
 	}
 	account.Customer = &customer
 	return &account, nil
 }
 
-func (g *AccountGateway) FindByCustomer(customer *entity.Customer) ([]*entity.Account, error) {
-	stmt, err := g.db.Prepare("select id, balance, created_at, updated_at from `account` where customer_id = ?")
+func (g *AccountGateway) FindByCustomer(ctx context.Context, customer *entity.Customer) ([]*entity.Account, error) {
+	stmt, err := connFrom(ctx, g.db).PrepareContext(ctx, "select id, balance, version, created_at, updated_at from `account` where customer_id = ?")
 	if err != nil {
 		return nil, err
 	}
 	defer stmt.Close()
-	rows, err := stmt.Query(customer.Id)
+	rows, err := stmt.QueryContext(ctx, customer.Id)
 	if err != nil {
 		return nil, err
 	}
@@ -91,6 +85,7 @@ func (g *AccountGateway) FindByCustomer(customer *entity.Customer) ([]*entity.Ac
 		dest := []any{
 			&account.Id,
 			&account.Balance,
+			&account.Version,
 			&account.CreatedAt,
 			&account.UpdatedAt,
 		}
@@ -103,19 +98,54 @@ func (g *AccountGateway) FindByCustomer(customer *entity.Customer) ([]*entity.Ac
 	return accounts, nil
 }
 
-func (g *AccountGateway) Update(account *entity.Account) error {
-	stmt, err := g.db.Prepare("update `account` set balance = ?, created_at = ?, updated_at = ? where id = ?")
+func (g *AccountGateway) Update(ctx context.Context, account *entity.Account) error {
+	changes := account.Changes()
+	if len(changes) == 0 {
+		return nil
+	}
+	stmt, err := connFrom(ctx, g.db).PrepareContext(ctx, "update `account` set balance = ?, version = ?, created_at = ?, updated_at = ? where id = ? and version = ?")
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 	args := []any{
 		account.Balance,
+		account.Version + len(changes),
 		account.CreatedAt,
 		account.UpdatedAt,
 		account.Id,
+		account.Version,
+	}
+	result, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: account %s is no longer at version %d", gateway.ErrConflict, account.Id, account.Version)
+	}
+	account.MarkChangesCommitted()
+	return nil
+}
+
+func insertAccount(ctx context.Context, db *sql.DB, account *entity.Account) error {
+	stmt, err := connFrom(ctx, db).PrepareContext(ctx, "insert into `account` (id, customer_id, balance, version, created_at, updated_at) values (?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	args := []any{
+		account.Id,
+		account.Customer.Id,
+		account.Balance,
+		account.Version + len(account.Changes()),
+		account.CreatedAt,
+		account.UpdatedAt,
 	}
-	if _, err := stmt.Exec(args...); err != nil {
+	if _, err := stmt.ExecContext(ctx, args...); err != nil {
 		return err
 	}
 	return nil
@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/josimarz/fc-eda-challenge/internal/entity"
@@ -14,8 +15,8 @@ func NewCustomerGateway(db *sql.DB) *CustomerGateway {
 	return &CustomerGateway{db}
 }
 
-func (g *CustomerGateway) Create(customer *entity.Customer) error {
-	stmt, err := g.db.Prepare("insert into `customer` (id, name, email, created_at, updated_at) values (?, ?, ?, ?, ?)")
+func (g *CustomerGateway) Create(ctx context.Context, customer *entity.Customer) error {
+	stmt, err := g.db.PrepareContext(ctx, "insert into `customer` (id, name, email, phone, tax_id, created_at, updated_at) values (?, ?, ?, ?, ?, ?, ?)")
 	if err != nil {
 		return err
 	}
@@ -24,17 +25,19 @@ func (g *CustomerGateway) Create(customer *entity.Customer) error {
 		customer.Id,
 		customer.Name,
 		customer.Email,
+		customer.Phone,
+		customer.TaxId,
 		customer.CreatedAt,
 		customer.UpdatedAt,
 	}
-	if _, err := stmt.Exec(args...); err != nil {
+	if _, err := stmt.ExecContext(ctx, args...); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (g *CustomerGateway) FindById(id string) (*entity.Customer, error) {
-	stmt, err := g.db.Prepare("select id, name, email, created_at, updated_at from `customer` where id = ?")
+func (g *CustomerGateway) FindById(ctx context.Context, id string) (*entity.Customer, error) {
+	stmt, err := g.db.PrepareContext(ctx, "select id, name, email, phone, tax_id, created_at, updated_at from `customer` where id = ?")
 	if err != nil {
 		return nil, err
 	}
@@ -44,22 +47,24 @@ func (g *CustomerGateway) FindById(id string) (*entity.Customer, error) {
 		&customer.Id,
 		&customer.Name,
 		&customer.Email,
+		&customer.Phone,
+		&customer.TaxId,
 		&customer.CreatedAt,
 		&customer.UpdatedAt,
 	}
-	if err := stmt.QueryRow(id).Scan(dest...); err != nil {
+	if err := stmt.QueryRowContext(ctx, id).Scan(dest...); err != nil {
 		return nil, err
 	}
 	return customer, nil
 }
 
-func (g *CustomerGateway) FindAll() ([]*entity.Customer, error) {
-	stmt, err := g.db.Prepare("select id, name, email, created_at, updated_at from `customer`")
+func (g *CustomerGateway) FindAll(ctx context.Context) ([]*entity.Customer, error) {
+	stmt, err := g.db.PrepareContext(ctx, "select id, name, email, phone, tax_id, created_at, updated_at from `customer`")
 	if err != nil {
 		return nil, err
 	}
 	defer stmt.Close()
-	rows, err := stmt.Query()
+	rows, err := stmt.QueryContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -71,6 +76,8 @@ func (g *CustomerGateway) FindAll() ([]*entity.Customer, error) {
 			&customer.Id,
 			&customer.Name,
 			&customer.Email,
+			&customer.Phone,
+			&customer.TaxId,
 			&customer.CreatedAt,
 			&customer.UpdatedAt,
 		}
@@ -82,8 +89,8 @@ func (g *CustomerGateway) FindAll() ([]*entity.Customer, error) {
 	return customers, nil
 }
 
-func (g *CustomerGateway) Update(customer *entity.Customer) error {
-	stmt, err := g.db.Prepare("update `customer` set name = ?, email = ?, created_at = ?, updated_at = ? where id = ?")
+func (g *CustomerGateway) Update(ctx context.Context, customer *entity.Customer) error {
+	stmt, err := g.db.PrepareContext(ctx, "update `customer` set name = ?, email = ?, phone = ?, tax_id = ?, created_at = ?, updated_at = ? where id = ?")
 	if err != nil {
 		return err
 	}
@@ -91,23 +98,25 @@ func (g *CustomerGateway) Update(customer *entity.Customer) error {
 	args := []any{
 		customer.Name,
 		customer.Email,
+		customer.Phone,
+		customer.TaxId,
 		customer.CreatedAt,
 		customer.UpdatedAt,
 		customer.Id,
 	}
-	if _, err := stmt.Exec(args...); err != nil {
+	if _, err := stmt.ExecContext(ctx, args...); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (g *CustomerGateway) Delete(customer *entity.Customer) error {
-	stmt, err := g.db.Prepare("delete from `customer` where id = ?")
+func (g *CustomerGateway) Delete(ctx context.Context, customer *entity.Customer) error {
+	stmt, err := g.db.PrepareContext(ctx, "delete from `customer` where id = ?")
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
-	if _, err := stmt.Exec(customer.Id); err != nil {
+	if _, err := stmt.ExecContext(ctx, customer.Id); err != nil {
 		return err
 	}
 	return nil
@@ -0,0 +1,86 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/josimarz/fc-eda-challenge/internal/entity"
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+)
+
+const CustomerBalanceProjectionName = "customer_balance"
+
+type CustomerBalanceProjection struct {
+	db *sql.DB
+}
+
+func NewCustomerBalanceProjection(db *sql.DB) *CustomerBalanceProjection {
+	return &CustomerBalanceProjection{db}
+}
+
+func (p *CustomerBalanceProjection) Name() string {
+	return CustomerBalanceProjectionName
+}
+
+func (p *CustomerBalanceProjection) Apply(ctx context.Context, envelope *events.Envelope) error {
+	var sign float64
+	switch envelope.Type {
+	case entity.AccountOpened:
+		return p.open(ctx, envelope)
+	case entity.MoneyDeposited, entity.TransferCredited:
+		sign = 1
+	case entity.MoneyWithdrawn, entity.TransferDebited:
+		sign = -1
+	default:
+		return nil
+	}
+	event := entity.AccountEvent{}
+	if err := envelope.Decode(&event); err != nil {
+		return events.Permanent(err)
+	}
+	query := `
+		update
+			customer_balance cb
+				join customer_balance_account cba on (cb.customer_id = cba.customer_id)
+		set
+			cb.total_balance = cb.total_balance + ?,
+			cb.updated_at = ?
+		where
+			cba.account_id = ?`
+	args := []any{
+		sign * event.Amount,
+		envelope.Time,
+		event.AccountId,
+	}
+	if _, err := connFrom(ctx, p.db).ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *CustomerBalanceProjection) Reset(ctx context.Context) error {
+	conn := connFrom(ctx, p.db)
+	if _, err := conn.ExecContext(ctx, "delete from `customer_balance_account`"); err != nil {
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, "delete from `customer_balance`"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *CustomerBalanceProjection) open(ctx context.Context, envelope *events.Envelope) error {
+	event := entity.AccountEvent{}
+	if err := envelope.Decode(&event); err != nil {
+		return events.Permanent(err)
+	}
+	conn := connFrom(ctx, p.db)
+	if _, err := conn.ExecContext(ctx, "insert into `customer_balance_account` (account_id, customer_id) values (?, ?)", event.AccountId, event.CustomerId); err != nil {
+		return err
+	}
+	query := "insert into `customer_balance` (customer_id, accounts, total_balance, updated_at) values (?, 1, 0, ?) on duplicate key update accounts = accounts + 1, updated_at = values(updated_at)"
+	if _, err := conn.ExecContext(ctx, query, event.CustomerId, envelope.Time); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/josimarz/fc-eda-challenge/internal/gateway"
+	"github.com/josimarz/fc-eda-challenge/pkg/eventstore"
+)
+
+var transientErrorNumbers = map[uint16]bool{
+	1040: true,
+	1205: true,
+	1213: true,
+	2006: true,
+	2013: true,
+}
+
+func IsTransient(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	if errors.Is(err, eventstore.ErrConcurrencyConflict) || errors.Is(err, gateway.ErrConflict) {
+		return true
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return transientErrorNumbers[mysqlErr.Number]
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
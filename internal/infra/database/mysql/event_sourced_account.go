@@ -0,0 +1,226 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/josimarz/fc-eda-challenge/internal/entity"
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/josimarz/fc-eda-challenge/pkg/eventstore"
+)
+
+type EventSourcedAccountGateway struct {
+	db               *sql.DB
+	store            eventstore.Store
+	source           string
+	snapshotInterval int
+}
+
+func NewEventSourcedAccountGateway(db *sql.DB, store eventstore.Store, source string, snapshotInterval int) *EventSourcedAccountGateway {
+	return &EventSourcedAccountGateway{db, store, source, snapshotInterval}
+}
+
+func (g *EventSourcedAccountGateway) Create(ctx context.Context, account *entity.Account) error {
+	return withTx(ctx, g.db, func(ctx context.Context, tx *sql.Tx) error {
+		if err := insertAccount(ctx, g.db, account); err != nil {
+			return err
+		}
+		return g.save(ctx, account)
+	})
+}
+
+func (g *EventSourcedAccountGateway) FindById(ctx context.Context, id string) (*entity.Account, error) {
+	account, err := g.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	customer, err := NewCustomerGateway(g.db).FindById(ctx, account.Customer.Id)
+	if err != nil {
+		return nil, err
+	}
+	account.Customer = customer
+	return account, nil
+}
+
+func (g *EventSourcedAccountGateway) FindByCustomer(ctx context.Context, customer *entity.Customer) ([]*entity.Account, error) {
+	stmt, err := g.db.PrepareContext(ctx, "select id from `account` where customer_id = ?")
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+	rows, err := stmt.QueryContext(ctx, customer.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	var accounts []*entity.Account
+	for _, id := range ids {
+		account, err := g.load(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		account.Customer = customer
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+func (g *EventSourcedAccountGateway) Update(ctx context.Context, account *entity.Account) error {
+	return g.save(ctx, account)
+}
+
+func (g *EventSourcedAccountGateway) load(ctx context.Context, id string) (*entity.Account, error) {
+	snapshot, err := g.findSnapshot(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	fromVersion := 1
+	if snapshot != nil {
+		fromVersion = snapshot.Version + 1
+	}
+	stored, err := g.store.ReadStream(ctx, accountStream(id), fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil && len(stored) == 0 {
+		if stored, err = g.backfill(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	history := make([]entity.AccountEvent, 0, len(stored))
+	for _, s := range stored {
+		event := entity.AccountEvent{}
+		if err := s.Envelope.Decode(&event); err != nil {
+			return nil, err
+		}
+		event.Type = s.Envelope.Type
+		event.OccurredAt = s.Envelope.Time
+		history = append(history, event)
+	}
+	return entity.RebuildAccount(snapshot, history), nil
+}
+
+func (g *EventSourcedAccountGateway) save(ctx context.Context, account *entity.Account) error {
+	changes := account.Changes()
+	if len(changes) == 0 {
+		return nil
+	}
+	envelopes, err := g.envelopes(ctx, changes)
+	if err != nil {
+		return err
+	}
+	err = withTx(ctx, g.db, func(ctx context.Context, tx *sql.Tx) error {
+		if err := g.store.Append(ctx, accountStream(account.Id), account.Version, envelopes...); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "update `account` set balance = ?, version = ?, updated_at = ? where id = ?", account.Balance, account.Version+len(changes), account.UpdatedAt, account.Id)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	previous := account.Version
+	account.MarkChangesCommitted()
+	if g.snapshotInterval > 0 && previous/g.snapshotInterval != account.Version/g.snapshotInterval {
+		if err := g.saveSnapshot(ctx, account); err != nil {
+			log.Printf("[Snapshot] account %s at version %d: %s\n", account.Id, account.Version, err)
+		}
+	}
+	return nil
+}
+
+func (g *EventSourcedAccountGateway) backfill(ctx context.Context, id string) ([]*eventstore.StoredEvent, error) {
+	stmt, err := g.db.PrepareContext(ctx, "select customer_id, balance, created_at, updated_at from `account` where id = ?")
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+	account := &entity.Account{}
+	var customerId string
+	if err := stmt.QueryRowContext(ctx, id).Scan(&customerId, &account.Balance, &account.CreatedAt, &account.UpdatedAt); err != nil {
+		return nil, err
+	}
+	history := []entity.AccountEvent{{Type: entity.AccountOpened, AccountId: id, CustomerId: customerId, OccurredAt: account.CreatedAt}}
+	if account.Balance > 0 {
+		history = append(history, entity.AccountEvent{Type: entity.MoneyDeposited, AccountId: id, Amount: account.Balance, OccurredAt: account.UpdatedAt})
+	}
+	envelopes, err := g.envelopes(ctx, history)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.store.Append(ctx, accountStream(id), 0, envelopes...); err != nil && !errors.Is(err, eventstore.ErrConcurrencyConflict) {
+		return nil, err
+	}
+	return g.store.ReadStream(ctx, accountStream(id), 1)
+}
+
+func (g *EventSourcedAccountGateway) envelopes(ctx context.Context, changes []entity.AccountEvent) ([]*events.Envelope, error) {
+	envelopes := make([]*events.Envelope, 0, len(changes))
+	for _, change := range changes {
+		envelope, err := events.NewEnvelope(ctx, g.source, events.NewTypedEvent(change.Type, change))
+		if err != nil {
+			return nil, err
+		}
+		envelope.Time = change.OccurredAt
+		envelopes = append(envelopes, envelope)
+	}
+	return envelopes, nil
+}
+
+func (g *EventSourcedAccountGateway) findSnapshot(ctx context.Context, id string) (*entity.Account, error) {
+	stmt, err := g.db.PrepareContext(ctx, "select customer_id, balance, version, created_at, updated_at from `account_snapshot` where account_id = ?")
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+	snapshot := &entity.Account{Customer: &entity.Customer{}}
+	snapshot.Id = id
+	dest := []any{
+		&snapshot.Customer.Id,
+		&snapshot.Balance,
+		&snapshot.Version,
+		&snapshot.CreatedAt,
+		&snapshot.UpdatedAt,
+	}
+	if err := stmt.QueryRowContext(ctx, id).Scan(dest...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+func (g *EventSourcedAccountGateway) saveSnapshot(ctx context.Context, account *entity.Account) error {
+	stmt, err := connFrom(ctx, g.db).PrepareContext(ctx, "insert into `account_snapshot` (account_id, customer_id, balance, version, created_at, updated_at) values (?, ?, ?, ?, ?, ?) on duplicate key update balance = values(balance), version = values(version), updated_at = values(updated_at)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	args := []any{
+		account.Id,
+		account.Customer.Id,
+		account.Balance,
+		account.Version,
+		account.CreatedAt,
+		account.UpdatedAt,
+	}
+	if _, err := stmt.ExecContext(ctx, args...); err != nil {
+		return err
+	}
+	return nil
+}
+
+func accountStream(id string) string {
+	return "account-" + id
+}
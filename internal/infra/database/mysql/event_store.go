@@ -0,0 +1,198 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/josimarz/fc-eda-challenge/pkg/eventstore"
+)
+
+const selectStoredEvent = `
+	select
+		position,
+		stream_id,
+		version,
+		id,
+		type,
+		schema_version,
+		source,
+		correlation_id,
+		causation_id,
+		metadata,
+		payload,
+		occurred_at
+	from
+		event_store`
+
+type EventStore struct {
+	db *sql.DB
+}
+
+func NewEventStore(db *sql.DB) *EventStore {
+	return &EventStore{db}
+}
+
+// Append writes envelopes to the end of streamId, skipping those already
+// recorded. A duplicate key during the insert is reported as a concurrency
+// conflict: inside an outer transaction the rows inserted before it cannot be
+// undone here, so the caller must roll back and retry.
+func (s *EventStore) Append(ctx context.Context, streamId string, expectedVersion int, envelopes ...*events.Envelope) error {
+	return withTx(ctx, s.db, func(ctx context.Context, tx *sql.Tx) error {
+		var version int
+		row := tx.QueryRowContext(ctx, "select coalesce(max(version), 0) from `event_store` where stream_id = ? for update", streamId)
+		if err := row.Scan(&version); err != nil {
+			return err
+		}
+		envelopes, err := s.unrecorded(ctx, tx, envelopes)
+		if err != nil || len(envelopes) == 0 {
+			return err
+		}
+		if expectedVersion != eventstore.AnyVersion && expectedVersion != version {
+			return fmt.Errorf("%w: %s is at version %d, expected %d", eventstore.ErrConcurrencyConflict, streamId, version, expectedVersion)
+		}
+		stmt, err := tx.PrepareContext(ctx, "insert into `event_store` (stream_id, version, id, type, schema_version, source, correlation_id, causation_id, metadata, payload, occurred_at) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		for _, envelope := range envelopes {
+			version++
+			metadata, err := json.Marshal(envelope.Metadata)
+			if err != nil {
+				return err
+			}
+			args := []any{
+				streamId,
+				version,
+				envelope.Id,
+				envelope.Type,
+				envelope.SchemaVersion,
+				envelope.Source,
+				envelope.CorrelationId,
+				envelope.CausationId,
+				metadata,
+				[]byte(envelope.Data),
+				envelope.Time,
+			}
+			if _, err := stmt.ExecContext(ctx, args...); err != nil {
+				var mysqlErr *mysql.MySQLError
+				if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+					return fmt.Errorf("%w: %s", eventstore.ErrConcurrencyConflict, mysqlErr.Message)
+				}
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// unrecorded drops the envelopes whose id is already in the event store, so an
+// append retried after its first attempt committed succeeds without writing the
+// events twice.
+func (s *EventStore) unrecorded(ctx context.Context, tx *sql.Tx, envelopes []*events.Envelope) ([]*events.Envelope, error) {
+	if len(envelopes) == 0 {
+		return nil, nil
+	}
+	ids := make([]any, len(envelopes))
+	for i, envelope := range envelopes {
+		ids[i] = envelope.Id
+	}
+	query := "select id from `event_store` where id in (?" + strings.Repeat(", ?", len(ids)-1) + ")"
+	rows, err := tx.QueryContext(ctx, query, ids...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	recorded := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		recorded[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	var pending []*events.Envelope
+	for _, envelope := range envelopes {
+		if !recorded[envelope.Id] {
+			pending = append(pending, envelope)
+		}
+	}
+	return pending, nil
+}
+
+func (s *EventStore) ReadStream(ctx context.Context, streamId string, fromVersion int) ([]*eventstore.StoredEvent, error) {
+	query := selectStoredEvent + " where stream_id = ? and version >= ? order by version"
+	return s.query(ctx, query, streamId, fromVersion)
+}
+
+func (s *EventStore) ReadAll(ctx context.Context, fromPosition int64, limit int) ([]*eventstore.StoredEvent, error) {
+	query := selectStoredEvent + " where position >= ? order by position"
+	args := []any{fromPosition}
+	if limit > 0 {
+		query += " limit ?"
+		args = append(args, limit)
+	}
+	return s.query(ctx, query, args...)
+}
+
+func (s *EventStore) query(ctx context.Context, query string, args ...any) ([]*eventstore.StoredEvent, error) {
+	stmt, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var stored []*eventstore.StoredEvent
+	for rows.Next() {
+		event, err := scanStoredEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		stored = append(stored, event)
+	}
+	return stored, rows.Err()
+}
+
+func scanStoredEvent(row scanner) (*eventstore.StoredEvent, error) {
+	envelope := &events.Envelope{
+		SpecVersion:     events.SpecVersion,
+		DataContentType: "application/json",
+	}
+	stored := &eventstore.StoredEvent{Envelope: envelope}
+	var metadata, payload []byte
+	dest := []any{
+		&stored.Position,
+		&stored.StreamId,
+		&stored.Version,
+		&envelope.Id,
+		&envelope.Type,
+		&envelope.SchemaVersion,
+		&envelope.Source,
+		&envelope.CorrelationId,
+		&envelope.CausationId,
+		&metadata,
+		&payload,
+		&envelope.Time,
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(metadata, &envelope.Metadata); err != nil {
+		return nil, err
+	}
+	envelope.Data = payload
+	return stored, nil
+}
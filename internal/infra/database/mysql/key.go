@@ -0,0 +1,159 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/josimarz/fc-eda-challenge/internal/entity"
+	"github.com/josimarz/fc-eda-challenge/internal/gateway"
+)
+
+type KeyGateway struct {
+	db *sql.DB
+}
+
+func NewKeyGateway(db *sql.DB) *KeyGateway {
+	return &KeyGateway{db}
+}
+
+func (g *KeyGateway) Create(ctx context.Context, key *entity.Key) error {
+	stmt, err := g.db.PrepareContext(ctx, "insert into `key` (id, type, value, account_id, created_at, updated_at) values (?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	args := []any{
+		key.Id,
+		key.Type,
+		key.Value,
+		key.Account.Id,
+		key.CreatedAt,
+		key.UpdatedAt,
+	}
+	if _, err := stmt.ExecContext(ctx, args...); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (g *KeyGateway) FindByValue(ctx context.Context, value string) (*entity.Key, error) {
+	stmt, err := g.db.PrepareContext(ctx, `
+		select
+			k.id,
+			k.type,
+			k.value,
+			k.created_at,
+			k.updated_at,
+			a.id,
+			a.balance,
+			a.created_at,
+			a.updated_at,
+			c.id,
+			c.name,
+			c.email,
+			c.created_at,
+			c.updated_at
+		from
+			`+"`key`"+` k
+				join account a on (k.account_id = a.id)
+				join customer c on (a.customer_id = c.id)
+		where
+			k.value = ?`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+	customer := entity.Customer{}
+	account := entity.Account{}
+	key := entity.Key{}
+	dest := []any{
+		&key.Id,
+		&key.Type,
+		&key.Value,
+		&key.CreatedAt,
+		&key.UpdatedAt,
+		&account.Id,
+		&account.Balance,
+		&account.CreatedAt,
+		&account.UpdatedAt,
+		&customer.Id,
+		&customer.Name,
+		&customer.Email,
+		&customer.CreatedAt,
+		&customer.UpdatedAt,
+	}
+	if err := stmt.QueryRowContext(ctx, value).Scan(dest...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: key %s", gateway.ErrNotFound, value)
+		}
+		return nil, err
+	}
+	account.Customer = &customer
+	key.Account = &account
+	return &key, nil
+}
+
+func (g *KeyGateway) FindByCustomer(ctx context.Context, customer *entity.Customer) ([]*entity.Key, error) {
+	stmt, err := g.db.PrepareContext(ctx, `
+		select
+			k.id,
+			k.type,
+			k.value,
+			k.created_at,
+			k.updated_at,
+			a.id,
+			a.balance,
+			a.created_at,
+			a.updated_at
+		from
+			`+"`key`"+` k
+				join account a on (k.account_id = a.id)
+		where
+			a.customer_id = ?`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+	rows, err := stmt.QueryContext(ctx, customer.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var keys []*entity.Key
+	for rows.Next() {
+		account := &entity.Account{}
+		key := &entity.Key{}
+		dest := []any{
+			&key.Id,
+			&key.Type,
+			&key.Value,
+			&key.CreatedAt,
+			&key.UpdatedAt,
+			&account.Id,
+			&account.Balance,
+			&account.CreatedAt,
+			&account.UpdatedAt,
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		account.Customer = customer
+		key.Account = account
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (g *KeyGateway) Delete(ctx context.Context, key *entity.Key) error {
+	stmt, err := g.db.PrepareContext(ctx, "delete from `key` where id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	if _, err := stmt.ExecContext(ctx, key.Id); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/josimarz/fc-eda-challenge/internal/entity"
+	"github.com/josimarz/fc-eda-challenge/internal/gateway"
+)
+
+const selectPaymentRequest = `
+	select
+		pr.id,
+		pr.amount,
+		pr.memo,
+		pr.status,
+		pr.expires_at,
+		pr.created_at,
+		pr.updated_at,
+		ra.id,
+		ra.customer_id,
+		pa.id,
+		pa.customer_id
+	from
+		payment_request pr
+			join account ra on (pr.requester_id = ra.id)
+			join account pa on (pr.payer_id = pa.id)`
+
+type PaymentRequestGateway struct {
+	db *sql.DB
+}
+
+func NewPaymentRequestGateway(db *sql.DB) *PaymentRequestGateway {
+	return &PaymentRequestGateway{db}
+}
+
+func (g *PaymentRequestGateway) Create(ctx context.Context, request *entity.PaymentRequest) error {
+	stmt, err := g.db.PrepareContext(ctx, "insert into `payment_request` (id, requester_id, payer_id, amount, memo, status, expires_at, created_at, updated_at) values (?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	args := []any{
+		request.Id,
+		request.Requester.Id,
+		request.Payer.Id,
+		request.Amount,
+		request.Memo,
+		request.Status,
+		request.ExpiresAt,
+		request.CreatedAt,
+		request.UpdatedAt,
+	}
+	if _, err := stmt.ExecContext(ctx, args...); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (g *PaymentRequestGateway) FindById(ctx context.Context, id string) (*entity.PaymentRequest, error) {
+	stmt, err := g.db.PrepareContext(ctx, selectPaymentRequest+" where pr.id = ?")
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+	return scanPaymentRequest(stmt.QueryRowContext(ctx, id))
+}
+
+func (g *PaymentRequestGateway) FindByPayerCustomer(ctx context.Context, customer *entity.Customer, status entity.PaymentRequestStatus) ([]*entity.PaymentRequest, error) {
+	query := selectPaymentRequest + " where pa.customer_id = ?"
+	args := []any{customer.Id}
+	if status != "" {
+		query += " and pr.status = ?"
+		args = append(args, status)
+	}
+	query += " order by pr.created_at"
+	return g.query(ctx, query, args...)
+}
+
+func (g *PaymentRequestGateway) FindExpired(ctx context.Context, now time.Time) ([]*entity.PaymentRequest, error) {
+	query := selectPaymentRequest + " where pr.status = ? and pr.expires_at <= ?"
+	return g.query(ctx, query, entity.PaymentRequestPending, now)
+}
+
+func (g *PaymentRequestGateway) Update(ctx context.Context, request *entity.PaymentRequest) error {
+	stmt, err := g.db.PrepareContext(ctx, "update `payment_request` set status = ?, updated_at = ? where id = ? and status = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	args := []any{
+		request.Status,
+		request.UpdatedAt,
+		request.Id,
+		entity.PaymentRequestPending,
+	}
+	result, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: payment request %s is no longer pending", gateway.ErrConflict, request.Id)
+	}
+	return nil
+}
+
+func (g *PaymentRequestGateway) Reopen(ctx context.Context, request *entity.PaymentRequest) error {
+	stmt, err := g.db.PrepareContext(ctx, "update `payment_request` set status = ?, updated_at = ? where id = ? and status = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	args := []any{
+		request.Status,
+		request.UpdatedAt,
+		request.Id,
+		entity.PaymentRequestAccepted,
+	}
+	result, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: payment request %s is no longer accepted", gateway.ErrConflict, request.Id)
+	}
+	return nil
+}
+
+func (g *PaymentRequestGateway) query(ctx context.Context, query string, args ...any) ([]*entity.PaymentRequest, error) {
+	stmt, err := g.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var requests []*entity.PaymentRequest
+	for rows.Next() {
+		request, err := scanPaymentRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPaymentRequest(row scanner) (*entity.PaymentRequest, error) {
+	request := &entity.PaymentRequest{
+		Requester: &entity.Account{Customer: &entity.Customer{}},
+		Payer:     &entity.Account{Customer: &entity.Customer{}},
+	}
+	dest := []any{
+		&request.Id,
+		&request.Amount,
+		&request.Memo,
+		&request.Status,
+		&request.ExpiresAt,
+		&request.CreatedAt,
+		&request.UpdatedAt,
+		&request.Requester.Id,
+		&request.Requester.Customer.Id,
+		&request.Payer.Id,
+		&request.Payer.Customer.Id,
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
@@ -0,0 +1,27 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type ProcessedMessageGateway struct {
+	db *sql.DB
+}
+
+func NewProcessedMessageGateway(db *sql.DB) *ProcessedMessageGateway {
+	return &ProcessedMessageGateway{db}
+}
+
+func (g *ProcessedMessageGateway) MarkProcessed(ctx context.Context, id string) (bool, error) {
+	result, err := connFrom(ctx, g.db).ExecContext(ctx, "insert ignore into `processed_message` (id, processed_at) values (?, ?)", id, time.Now())
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
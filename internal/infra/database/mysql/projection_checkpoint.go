@@ -0,0 +1,102 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/projection"
+)
+
+const projectionLockTimeout = 60
+
+type ProjectionCheckpointStore struct {
+	db *sql.DB
+}
+
+func NewProjectionCheckpointStore(db *sql.DB) *ProjectionCheckpointStore {
+	return &ProjectionCheckpointStore{db}
+}
+
+func (s *ProjectionCheckpointStore) Position(ctx context.Context, name string) (int64, error) {
+	stmt, err := s.db.PrepareContext(ctx, "select position from `projection_checkpoint` where name = ?")
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+	var position int64
+	if err := stmt.QueryRowContext(ctx, name).Scan(&position); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return position, nil
+}
+
+func (s *ProjectionCheckpointStore) Process(ctx context.Context, name, eventId string, position int64, apply func(ctx context.Context) error) (bool, error) {
+	applied := false
+	err := withTx(ctx, s.db, func(ctx context.Context, tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, "insert ignore into `projection_event` (name, event_id) values (?, ?)", name, eventId)
+		if err != nil {
+			return err
+		}
+		inserted, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if applied = inserted > 0; applied {
+			if err := apply(ctx); err != nil {
+				return err
+			}
+		}
+		if position > 0 {
+			query := "insert into `projection_checkpoint` (name, position, updated_at) values (?, ?, now()) on duplicate key update position = greatest(position, values(position)), updated_at = values(updated_at)"
+			if _, err := tx.ExecContext(ctx, query, name, position); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return applied, nil
+}
+
+func (s *ProjectionCheckpointStore) Reset(ctx context.Context, name string, reset func(ctx context.Context) error) error {
+	return withTx(ctx, s.db, func(ctx context.Context, tx *sql.Tx) error {
+		if err := reset(ctx); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "delete from `projection_event` where name = ?", name); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "delete from `projection_checkpoint` where name = ?", name); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// Lock holds a named MySQL lock on a dedicated connection while fn runs, so a
+// rebuild started from the projections command and the walletcore catch-up
+// loop never work on the same projection at the same time.
+func (s *ProjectionCheckpointStore) Lock(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	lock := "projection:" + name
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "select get_lock(?, ?)", lock, projectionLockTimeout).Scan(&acquired); err != nil {
+		return err
+	}
+	if acquired.Int64 != 1 {
+		return fmt.Errorf("%w: %s", projection.ErrLocked, name)
+	}
+	defer conn.ExecContext(context.Background(), "select release_lock(?)", lock)
+	return fn(ctx)
+}
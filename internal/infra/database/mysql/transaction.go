@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/josimarz/fc-eda-challenge/internal/entity"
@@ -14,8 +15,8 @@ func NewTransactionGateway(db *sql.DB) *TransactionGateway {
 	return &TransactionGateway{db}
 }
 
-func (g *TransactionGateway) Create(transaction *entity.Transaction) error {
-	stmt, err := g.db.Prepare("insert into transaction (id, from_id, to_id, amount, created_at, updated_at) values (?, ?, ?, ?, ?, ?)")
+func (g *TransactionGateway) Create(ctx context.Context, transaction *entity.Transaction) error {
+	stmt, err := g.db.PrepareContext(ctx, "insert into transaction (id, from_id, to_id, amount, created_at, updated_at) values (?, ?, ?, ?, ?, ?) on duplicate key update id = id")
 	if err != nil {
 		return err
 	}
@@ -28,7 +29,7 @@ func (g *TransactionGateway) Create(transaction *entity.Transaction) error {
 		transaction.CreatedAt,
 		transaction.UpdatedAt,
 	}
-	if _, err := stmt.Exec(args...); err != nil {
+	if _, err := stmt.ExecContext(ctx, args...); err != nil {
 		return err
 	}
 	return nil
@@ -0,0 +1,371 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/messaging"
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+)
+
+const pollInterval = 100 * time.Millisecond
+
+type consumerOptions struct {
+	retryBackoff time.Duration
+	errorHandler func(message *ckafka.Message, err error)
+	deadLetters  *Producer
+	maxAttempts  int
+	retries      *Producer
+	retryTiers   []time.Duration
+	transactions *Producer
+}
+
+type ConsumerOption func(*consumerOptions)
+
+func defaultConsumerOptions() consumerOptions {
+	return consumerOptions{
+		retryBackoff: time.Second,
+		errorHandler: func(message *ckafka.Message, err error) {
+			if message != nil {
+				log.Printf("[Kafka] %s: %s\n", message.TopicPartition, err)
+				return
+			}
+			log.Printf("[Kafka] %s\n", err)
+		},
+	}
+}
+
+func WithRetryBackoff(backoff time.Duration) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.retryBackoff = backoff
+	}
+}
+
+func WithDeadLetterQueue(producer *Producer, maxAttempts int) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.deadLetters = producer
+		o.maxAttempts = maxAttempts
+	}
+}
+
+func WithRetryTopics(producer *Producer, tiers ...time.Duration) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.retries = producer
+		o.retryTiers = tiers
+		if len(tiers) == 0 {
+			o.retryTiers = DefaultRetryTiers
+		}
+	}
+}
+
+func WithTransactions(producer *Producer) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.transactions = producer
+	}
+}
+
+func WithConsumerErrorHandler(handler func(message *ckafka.Message, err error)) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.errorHandler = handler
+	}
+}
+
+var _ messaging.Subscriber = (*Consumer)(nil)
+
+type attemptKey struct {
+	topic     string
+	partition int32
+	offset    ckafka.Offset
+}
+
+type partitionKey struct {
+	topic     string
+	partition int32
+}
+
+type handlerFailure struct {
+	err error
+}
+
+func (e *handlerFailure) Error() string {
+	return e.err.Error()
+}
+
+func (e *handlerFailure) Unwrap() error {
+	return e.err
+}
+
+type Consumer struct {
+	Topics   []string
+	consumer *ckafka.Consumer
+	options  consumerOptions
+	attempts map[attemptKey]int
+	mu       sync.Mutex
+	paused   map[partitionKey]time.Time
+}
+
+func NewConsumer(configMap *ckafka.ConfigMap, topics []string, opts ...ConsumerOption) (*Consumer, error) {
+	manual := ckafka.ConfigMap{}
+	for key, value := range *configMap {
+		manual[key] = value
+	}
+	manual["enable.auto.offset.store"] = false
+	consumer, err := ckafka.NewConsumer(&manual)
+	if err != nil {
+		return nil, err
+	}
+	c := &Consumer{
+		Topics:   topics,
+		consumer: consumer,
+		options:  defaultConsumerOptions(),
+		attempts: make(map[attemptKey]int),
+		paused:   make(map[partitionKey]time.Time),
+	}
+	for _, opt := range opts {
+		opt(&c.options)
+	}
+	subscriptions := topics
+	if c.options.retries != nil {
+		for _, topic := range topics {
+			subscriptions = append(subscriptions, RetryTopics(topic, c.options.retryTiers)...)
+		}
+	}
+	if err := consumer.SubscribeTopics(subscriptions, c.rebalance); err != nil {
+		consumer.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Consumer) Run(ctx context.Context, handler messaging.Handler) error {
+	for ctx.Err() == nil {
+		if err := c.resumeDue(); err != nil {
+			c.options.errorHandler(nil, err)
+		}
+		message, err := c.poll()
+		if err != nil {
+			var kerr ckafka.Error
+			if errors.As(err, &kerr) && kerr.IsFatal() {
+				return err
+			}
+			c.options.errorHandler(nil, err)
+			continue
+		}
+		if message == nil || c.isPaused(message) {
+			continue
+		}
+		if due := RetryDue(message); time.Now().Before(due) {
+			if err := c.pause(message, due); err != nil {
+				c.options.errorHandler(message, err)
+			}
+			continue
+		}
+		if err := c.process(ctx, message, handler); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			var kerr ckafka.Error
+			if errors.As(err, &kerr) && kerr.IsFatal() {
+				return err
+			}
+			if err := c.retry(ctx, message); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Consumer) Next(ctx context.Context) (*ckafka.Message, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		message, err := c.poll()
+		if message != nil || err != nil {
+			return message, err
+		}
+	}
+}
+
+func (c *Consumer) Rewind(message *ckafka.Message) error {
+	return c.consumer.Seek(message.TopicPartition, int(pollInterval/time.Millisecond))
+}
+
+func (c *Consumer) GroupMetadata() (*ckafka.ConsumerGroupMetadata, error) {
+	return c.consumer.GetConsumerGroupMetadata()
+}
+
+func (c *Consumer) Close() error {
+	if err := c.commit(); err != nil {
+		c.options.errorHandler(nil, err)
+	}
+	return c.consumer.Close()
+}
+
+func (c *Consumer) poll() (*ckafka.Message, error) {
+	message, err := c.consumer.ReadMessage(pollInterval)
+	if kerr, ok := err.(ckafka.Error); ok && kerr.Code() == ckafka.ErrTimedOut {
+		return nil, nil
+	}
+	return message, err
+}
+
+func (c *Consumer) process(ctx context.Context, message *ckafka.Message, handler messaging.Handler) error {
+	if c.options.transactions != nil {
+		err := c.options.transactions.Transact(ctx, c, message, func(ctx context.Context) error {
+			return c.invoke(ctx, message, handler)
+		})
+		var failure *handlerFailure
+		if !errors.As(err, &failure) {
+			return err
+		}
+		return c.options.transactions.Transact(ctx, c, message, func(ctx context.Context) error {
+			return c.fail(ctx, message, failure.err)
+		})
+	}
+	if err := c.handle(ctx, message, handler); err != nil {
+		return err
+	}
+	if _, err := c.consumer.StoreMessage(message); err != nil {
+		c.options.errorHandler(message, err)
+	}
+	return nil
+}
+
+func (c *Consumer) handle(ctx context.Context, message *ckafka.Message, handler messaging.Handler) error {
+	var failure *handlerFailure
+	if err := c.invoke(ctx, message, handler); errors.As(err, &failure) {
+		return c.fail(ctx, message, failure.err)
+	}
+	return nil
+}
+
+func (c *Consumer) invoke(ctx context.Context, message *ckafka.Message, handler messaging.Handler) error {
+	m := fromKafka(message)
+	if err := handler(messaging.MessageContext(ctx, m), m); err != nil {
+		return &handlerFailure{err}
+	}
+	delete(c.attempts, messageKey(message))
+	return nil
+}
+
+func (c *Consumer) fail(ctx context.Context, message *ckafka.Message, err error) error {
+	c.options.errorHandler(message, err)
+	key := messageKey(message)
+	c.attempts[key]++
+	attempts := c.attempts[key]
+	if c.options.retries != nil && !events.IsPermanent(err) {
+		if retryErr := c.options.retries.Retry(ctx, message, err, c.options.retryTiers); retryErr != nil {
+			c.options.errorHandler(message, retryErr)
+			return retryErr
+		}
+		delete(c.attempts, key)
+		return nil
+	}
+	exhausted := c.options.maxAttempts > 0 && attempts >= c.options.maxAttempts
+	if !events.IsPermanent(err) && !exhausted {
+		return err
+	}
+	deadLetters := c.options.deadLetters
+	if deadLetters == nil {
+		deadLetters = c.options.retries
+	}
+	if deadLetters == nil {
+		delete(c.attempts, key)
+		return nil
+	}
+	if dlqErr := deadLetters.DeadLetter(ctx, message, err, RetryAttempt(message)+attempts); dlqErr != nil {
+		c.options.errorHandler(message, dlqErr)
+		return dlqErr
+	}
+	delete(c.attempts, key)
+	return nil
+}
+
+func (c *Consumer) pause(message *ckafka.Message, due time.Time) error {
+	partition := ckafka.TopicPartition{Topic: message.TopicPartition.Topic, Partition: message.TopicPartition.Partition}
+	if err := c.consumer.Pause([]ckafka.TopicPartition{partition}); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.paused[partitionKey{*partition.Topic, partition.Partition}] = due
+	c.mu.Unlock()
+	return c.Rewind(message)
+}
+
+func (c *Consumer) isPaused(message *ckafka.Message) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.paused[partitionKey{*message.TopicPartition.Topic, message.TopicPartition.Partition}]
+	return ok
+}
+
+func (c *Consumer) resumeDue() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	var partitions []ckafka.TopicPartition
+	for key, due := range c.paused {
+		if now.Before(due) {
+			continue
+		}
+		topic := key.topic
+		partitions = append(partitions, ckafka.TopicPartition{Topic: &topic, Partition: key.partition})
+		delete(c.paused, key)
+	}
+	if len(partitions) == 0 {
+		return nil
+	}
+	return c.consumer.Resume(partitions)
+}
+
+func (c *Consumer) retry(ctx context.Context, message *ckafka.Message) error {
+	if err := c.Rewind(message); err != nil {
+		return err
+	}
+	select {
+	case <-time.After(c.options.retryBackoff):
+	case <-ctx.Done():
+	}
+	return nil
+}
+
+func messageKey(message *ckafka.Message) attemptKey {
+	return attemptKey{*message.TopicPartition.Topic, message.TopicPartition.Partition, message.TopicPartition.Offset}
+}
+
+func (c *Consumer) commit() error {
+	if _, err := c.consumer.Commit(); err != nil {
+		var kerr ckafka.Error
+		if errors.As(err, &kerr) && kerr.Code() == ckafka.ErrNoOffset {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func (c *Consumer) rebalance(consumer *ckafka.Consumer, event ckafka.Event) error {
+	switch ev := event.(type) {
+	case ckafka.AssignedPartitions:
+		log.Printf("[Kafka] partitions assigned: %v\n", ev.Partitions)
+	case ckafka.RevokedPartitions:
+		log.Printf("[Kafka] partitions revoked: %v\n", ev.Partitions)
+		c.mu.Lock()
+		for _, partition := range ev.Partitions {
+			delete(c.paused, partitionKey{*partition.Topic, partition.Partition})
+		}
+		c.mu.Unlock()
+		if !consumer.AssignmentLost() {
+			if err := c.commit(); err != nil {
+				c.options.errorHandler(nil, err)
+			}
+		}
+	}
+	return nil
+}
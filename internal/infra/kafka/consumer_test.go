@@ -0,0 +1,131 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/messaging"
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/stretchr/testify/suite"
+)
+
+type ConsumerTestSuite struct {
+	suite.Suite
+	ctx     context.Context
+	cancel  context.CancelFunc
+	cluster *ckafka.MockCluster
+	mu      sync.Mutex
+	errs    []error
+}
+
+func (suite *ConsumerTestSuite) SetupTest() {
+	suite.ctx, suite.cancel = context.WithTimeout(context.Background(), 30*time.Second)
+	cluster, err := ckafka.NewMockCluster(1)
+	suite.Require().Nil(err)
+	suite.cluster = cluster
+	suite.errs = nil
+}
+
+func (suite *ConsumerTestSuite) TearDownTest() {
+	suite.cluster.Close()
+	suite.cancel()
+}
+
+func (suite *ConsumerTestSuite) newConsumer(opts ...ConsumerOption) *Consumer {
+	opts = append([]ConsumerOption{
+		WithRetryBackoff(10 * time.Millisecond),
+		WithConsumerErrorHandler(func(message *ckafka.Message, err error) {
+			suite.mu.Lock()
+			defer suite.mu.Unlock()
+			suite.errs = append(suite.errs, err)
+		}),
+	}, opts...)
+	consumer, err := NewConsumer(&ckafka.ConfigMap{
+		"bootstrap.servers":  suite.cluster.BootstrapServers(),
+		"group.id":           "group",
+		"auto.offset.reset":  "earliest",
+		"session.timeout.ms": 6000,
+	}, []string{"test"}, opts...)
+	suite.Require().Nil(err)
+	return consumer
+}
+
+func (suite *ConsumerTestSuite) publish(ids ...string) {
+	producer, err := NewProducer(&ckafka.ConfigMap{"bootstrap.servers": suite.cluster.BootstrapServers()}, "test", nil)
+	suite.Require().Nil(err)
+	defer producer.Close(suite.ctx)
+	for _, id := range ids {
+		suite.Require().Nil(producer.PublishSync(suite.ctx, events.NewTypedEvent("test", testPayload{id, 10}), []byte("test"), "test"))
+	}
+}
+
+func (suite *ConsumerTestSuite) run(consumer *Consumer, stopAfter int, handler func(payload testPayload) error) []string {
+	ctx, cancel := context.WithCancel(suite.ctx)
+	defer cancel()
+	var ids []string
+	err := consumer.Run(ctx, func(ctx context.Context, message *messaging.Message) error {
+		envelope, err := messaging.DecodeMessage(message, newTestRegistry(suite.T()))
+		suite.Require().Nil(err)
+		payload := testPayload{}
+		suite.Require().Nil(envelope.Decode(&payload))
+		ids = append(ids, payload.Id)
+		if len(ids) == stopAfter {
+			cancel()
+		}
+		return handler(payload)
+	})
+	suite.Nil(err)
+	return ids
+}
+
+func (suite *ConsumerTestSuite) TestRun() {
+	suite.publish("1", "2")
+	consumer := suite.newConsumer()
+	ids := suite.run(consumer, 2, func(payload testPayload) error { return nil })
+	suite.Equal([]string{"1", "2"}, ids)
+	suite.Nil(consumer.Close())
+
+	suite.publish("3")
+	consumer = suite.newConsumer()
+	defer consumer.Close()
+	ids = suite.run(consumer, 1, func(payload testPayload) error { return nil })
+	suite.Equal([]string{"3"}, ids)
+}
+
+func (suite *ConsumerTestSuite) TestRun_WithFailingHandler() {
+	suite.publish("1", "2")
+	consumer := suite.newConsumer()
+	defer consumer.Close()
+	attempts := 0
+	ids := suite.run(consumer, 3, func(payload testPayload) error {
+		if payload.Id == "1" && attempts == 0 {
+			attempts++
+			return errors.New("database unavailable")
+		}
+		return nil
+	})
+	suite.Equal([]string{"1", "1", "2"}, ids)
+	suite.Len(suite.errs, 1)
+}
+
+func (suite *ConsumerTestSuite) TestRun_WithPermanentError() {
+	suite.publish("1", "2")
+	consumer := suite.newConsumer()
+	defer consumer.Close()
+	ids := suite.run(consumer, 2, func(payload testPayload) error {
+		if payload.Id == "1" {
+			return events.Permanent(errors.New("malformed payload"))
+		}
+		return nil
+	})
+	suite.Equal([]string{"1", "2"}, ids)
+	suite.Len(suite.errs, 1)
+}
+
+func TestConsumerTestSuite(t *testing.T) {
+	suite.Run(t, new(ConsumerTestSuite))
+}
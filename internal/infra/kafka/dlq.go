@@ -0,0 +1,231 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+const (
+	DeadLetterSuffix      = ".dlq"
+	deadLetterPrefix      = "dlq_"
+	dlqErrorHeader        = "dlq_error"
+	dlqAttemptsHeader     = "dlq_attempts"
+	dlqTopicHeader        = "dlq_topic"
+	dlqPartitionHeader    = "dlq_partition"
+	dlqOffsetHeader       = "dlq_offset"
+	dlqFailedAtHeader     = "dlq_failed_at"
+	deadLetterReadTimeout = 10 * time.Second
+	deadLetterReplayGroup = "dlq-replay"
+)
+
+var ErrNotDeadLetter = errors.New("message is not a dead letter")
+
+type DeadLetter struct {
+	Message   *ckafka.Message
+	Topic     string
+	Partition int32
+	Offset    int64
+	Error     string
+	Attempts  int
+	FailedAt  time.Time
+}
+
+func DeadLetterTopic(topic string) string {
+	return topic + DeadLetterSuffix
+}
+
+func ParseDeadLetter(message *ckafka.Message) (*DeadLetter, error) {
+	headers := make(map[string]string)
+	for _, header := range message.Headers {
+		headers[header.Key] = string(header.Value)
+	}
+	topic, ok := headers[dlqTopicHeader]
+	if !ok {
+		return nil, ErrNotDeadLetter
+	}
+	dl := &DeadLetter{
+		Message: message,
+		Topic:   topic,
+		Error:   headers[dlqErrorHeader],
+	}
+	partition, err := strconv.ParseInt(headers[dlqPartitionHeader], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotDeadLetter, err)
+	}
+	dl.Partition = int32(partition)
+	if dl.Offset, err = strconv.ParseInt(headers[dlqOffsetHeader], 10, 64); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotDeadLetter, err)
+	}
+	if dl.Attempts, err = strconv.Atoi(headers[dlqAttemptsHeader]); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotDeadLetter, err)
+	}
+	if dl.FailedAt, err = time.Parse(time.RFC3339Nano, headers[dlqFailedAtHeader]); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotDeadLetter, err)
+	}
+	return dl, nil
+}
+
+func (p *Producer) DeadLetter(ctx context.Context, message *ckafka.Message, cause error, attempts int) error {
+	origin := originOf(message)
+	topic := DeadLetterTopic(*origin.Topic)
+	headers := append(withoutHeaders(message.Headers, deadLetterPrefix),
+		ckafka.Header{Key: dlqErrorHeader, Value: []byte(cause.Error())},
+		ckafka.Header{Key: dlqAttemptsHeader, Value: []byte(strconv.Itoa(attempts))},
+		ckafka.Header{Key: dlqTopicHeader, Value: []byte(*origin.Topic)},
+		ckafka.Header{Key: dlqPartitionHeader, Value: []byte(strconv.Itoa(int(origin.Partition)))},
+		ckafka.Header{Key: dlqOffsetHeader, Value: []byte(strconv.FormatInt(int64(origin.Offset), 10))},
+		ckafka.Header{Key: dlqFailedAtHeader, Value: []byte(time.Now().UTC().Format(time.RFC3339Nano))},
+	)
+	return p.produceSync(ctx, &ckafka.Message{
+		TopicPartition: ckafka.TopicPartition{Topic: &topic, Partition: ckafka.PartitionAny},
+		Value:          message.Value,
+		Key:            message.Key,
+		Headers:        headers,
+	})
+}
+
+func (p *Producer) Replay(ctx context.Context, dl *DeadLetter) error {
+	topic := dl.Topic
+	return p.produceSync(ctx, &ckafka.Message{
+		TopicPartition: ckafka.TopicPartition{Topic: &topic, Partition: ckafka.PartitionAny},
+		Value:          dl.Message.Value,
+		Key:            dl.Message.Key,
+		Headers:        withoutHeaders(withoutHeaders(dl.Message.Headers, deadLetterPrefix), retryPrefix),
+	})
+}
+
+// ReadDeadLetters reads every dead letter currently in <topic>.dlq. Each
+// partition is read until the consumer reports its end instead of until the
+// high watermark, since a transactionally written topic ends with a commit
+// marker that is never delivered.
+func ReadDeadLetters(ctx context.Context, configMap *ckafka.ConfigMap, topic string) ([]*DeadLetter, error) {
+	reader := ckafka.ConfigMap{}
+	for key, value := range *configMap {
+		reader[key] = value
+	}
+	reader["group.id"] = "dlq-reader"
+	reader["enable.auto.commit"] = false
+	reader["enable.partition.eof"] = true
+	consumer, err := ckafka.NewConsumer(&reader)
+	if err != nil {
+		return nil, err
+	}
+	defer consumer.Close()
+	dlqTopic := DeadLetterTopic(topic)
+	metadata, err := consumer.GetMetadata(&dlqTopic, false, int(deadLetterReadTimeout/time.Millisecond))
+	if err != nil {
+		return nil, err
+	}
+	var partitions []ckafka.TopicPartition
+	remaining := make(map[int32]bool)
+	for _, partition := range metadata.Topics[dlqTopic].Partitions {
+		low, high, err := consumer.QueryWatermarkOffsets(dlqTopic, partition.ID, int(deadLetterReadTimeout/time.Millisecond))
+		if err != nil {
+			return nil, err
+		}
+		if high > low {
+			partitions = append(partitions, ckafka.TopicPartition{Topic: &dlqTopic, Partition: partition.ID, Offset: ckafka.Offset(low)})
+			remaining[partition.ID] = true
+		}
+	}
+	if len(partitions) == 0 {
+		return nil, nil
+	}
+	if err := consumer.Assign(partitions); err != nil {
+		return nil, err
+	}
+	var letters []*DeadLetter
+	for len(remaining) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		switch e := consumer.Poll(int(pollInterval / time.Millisecond)).(type) {
+		case *ckafka.Message:
+			if e.TopicPartition.Error != nil {
+				return nil, e.TopicPartition.Error
+			}
+			if dl, err := ParseDeadLetter(e); err == nil {
+				letters = append(letters, dl)
+			}
+		case ckafka.PartitionEOF:
+			delete(remaining, e.Partition)
+		case ckafka.Error:
+			return nil, e
+		}
+	}
+	return letters, nil
+}
+
+// ReplayedOffsets returns, for each partition of <topic>.dlq, the offset below
+// which every dead letter was already replayed, as committed by MarkReplayed
+// for the dlq-replay consumer group.
+func ReplayedOffsets(ctx context.Context, configMap *ckafka.ConfigMap, topic string) (map[int32]int64, error) {
+	consumer, err := newReplayConsumer(configMap)
+	if err != nil {
+		return nil, err
+	}
+	defer consumer.Close()
+	dlqTopic := DeadLetterTopic(topic)
+	metadata, err := consumer.GetMetadata(&dlqTopic, false, int(deadLetterReadTimeout/time.Millisecond))
+	if err != nil {
+		return nil, err
+	}
+	var partitions []ckafka.TopicPartition
+	for _, partition := range metadata.Topics[dlqTopic].Partitions {
+		partitions = append(partitions, ckafka.TopicPartition{Topic: &dlqTopic, Partition: partition.ID})
+	}
+	offsets := make(map[int32]int64)
+	if len(partitions) == 0 {
+		return offsets, nil
+	}
+	committed, err := consumer.Committed(partitions, int(deadLetterReadTimeout/time.Millisecond))
+	if err != nil {
+		return nil, err
+	}
+	for _, partition := range committed {
+		if partition.Offset >= 0 {
+			offsets[partition.Partition] = int64(partition.Offset)
+		}
+	}
+	return offsets, nil
+}
+
+// MarkReplayed commits the position after dl for the dlq-replay consumer group,
+// so a later replay without positions skips it.
+func MarkReplayed(ctx context.Context, configMap *ckafka.ConfigMap, dl *DeadLetter) error {
+	consumer, err := newReplayConsumer(configMap)
+	if err != nil {
+		return err
+	}
+	defer consumer.Close()
+	position := dl.Message.TopicPartition
+	position.Offset++
+	_, err = consumer.CommitOffsets([]ckafka.TopicPartition{position})
+	return err
+}
+
+func newReplayConsumer(configMap *ckafka.ConfigMap) (*ckafka.Consumer, error) {
+	replay := ckafka.ConfigMap{}
+	for key, value := range *configMap {
+		replay[key] = value
+	}
+	replay["group.id"] = deadLetterReplayGroup
+	replay["enable.auto.commit"] = false
+	return ckafka.NewConsumer(&replay)
+}
+
+func withoutHeaders(headers []ckafka.Header, prefix string) []ckafka.Header {
+	var kept []ckafka.Header
+	for _, header := range headers {
+		if !strings.HasPrefix(header.Key, prefix) {
+			kept = append(kept, header)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,96 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/messaging"
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDeadLetter_WithRegularMessage(t *testing.T) {
+	topic := "test"
+	_, err := ParseDeadLetter(&ckafka.Message{TopicPartition: ckafka.TopicPartition{Topic: &topic}})
+	assert.ErrorIs(t, err, ErrNotDeadLetter)
+}
+
+func (suite *ConsumerTestSuite) TestRun_WithDeadLetterQueue() {
+	suite.publish("1", "2", "3")
+	producer, err := NewProducer(&ckafka.ConfigMap{"bootstrap.servers": suite.cluster.BootstrapServers()}, "test", nil)
+	suite.Require().Nil(err)
+	defer producer.Close(suite.ctx)
+	consumer := suite.newConsumer(WithDeadLetterQueue(producer, 2))
+	defer consumer.Close()
+
+	ids := suite.run(consumer, 4, func(payload testPayload) error {
+		switch payload.Id {
+		case "1":
+			return errors.New("database unavailable")
+		case "2":
+			return events.Permanent(errors.New("malformed payload"))
+		}
+		return nil
+	})
+	suite.Equal([]string{"1", "1", "2", "3"}, ids)
+
+	configMap := &ckafka.ConfigMap{"bootstrap.servers": suite.cluster.BootstrapServers()}
+	letters, err := ReadDeadLetters(suite.ctx, configMap, "test")
+	suite.Require().Nil(err)
+	suite.Require().Len(letters, 2)
+	suite.Equal("test", letters[0].Topic)
+	suite.Equal(int64(0), letters[0].Offset)
+	suite.Equal(2, letters[0].Attempts)
+	suite.Equal("database unavailable", letters[0].Error)
+	suite.WithinDuration(time.Now(), letters[0].FailedAt, time.Minute)
+	suite.Equal(1, letters[1].Attempts)
+	suite.Equal("malformed payload", letters[1].Error)
+
+	offsets, err := ReplayedOffsets(suite.ctx, configMap, "test")
+	suite.Require().Nil(err)
+	suite.Empty(offsets)
+	suite.Nil(producer.Replay(suite.ctx, letters[0]))
+	suite.Nil(MarkReplayed(suite.ctx, configMap, letters[0]))
+	offsets, err = ReplayedOffsets(suite.ctx, configMap, "test")
+	suite.Require().Nil(err)
+	suite.Equal(map[int32]int64{letters[0].Message.TopicPartition.Partition: int64(letters[0].Message.TopicPartition.Offset) + 1}, offsets)
+	replayed := suite.newReader("test")
+	defer replayed.Close()
+	var last *ckafka.Message
+	for i := 0; i < 4; i++ {
+		last, err = replayed.Next(suite.ctx)
+		suite.Require().Nil(err)
+	}
+	envelope, err := messaging.DecodeMessage(fromKafka(last), newTestRegistry(suite.T()))
+	suite.Nil(err)
+	payload := testPayload{}
+	suite.Nil(envelope.Decode(&payload))
+	suite.Equal("1", payload.Id)
+	for _, header := range last.Headers {
+		suite.NotContains(header.Key, deadLetterPrefix)
+	}
+}
+
+func (suite *ConsumerTestSuite) newReader(topic string) *Consumer {
+	consumer, err := NewConsumer(&ckafka.ConfigMap{
+		"bootstrap.servers": suite.cluster.BootstrapServers(),
+		"group.id":          "reader",
+		"auto.offset.reset": "earliest",
+	}, []string{topic})
+	suite.Require().Nil(err)
+	return consumer
+}
+
+func TestReadDeadLetters_WithMissingTopic(t *testing.T) {
+	cluster, err := ckafka.NewMockCluster(1)
+	assert.Nil(t, err)
+	defer cluster.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	letters, err := ReadDeadLetters(ctx, &ckafka.ConfigMap{"bootstrap.servers": cluster.BootstrapServers()}, "missing")
+	assert.Nil(t, err)
+	assert.Empty(t, letters)
+}
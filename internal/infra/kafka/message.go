@@ -0,0 +1,31 @@
+package kafka
+
+import (
+	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/messaging"
+)
+
+func fromKafka(message *ckafka.Message) *messaging.Message {
+	m := &messaging.Message{
+		Partition: message.TopicPartition.Partition,
+		Offset:    int64(message.TopicPartition.Offset),
+		Key:       message.Key,
+		Value:     message.Value,
+		Timestamp: message.Timestamp,
+	}
+	if message.TopicPartition.Topic != nil {
+		m.Topic = *message.TopicPartition.Topic
+	}
+	for _, header := range message.Headers {
+		m.Headers = append(m.Headers, messaging.Header{Key: header.Key, Value: header.Value})
+	}
+	return m
+}
+
+func toKafkaHeaders(headers []messaging.Header) []ckafka.Header {
+	converted := make([]ckafka.Header, 0, len(headers))
+	for _, header := range headers {
+		converted = append(converted, ckafka.Header{Key: header.Key, Value: header.Value})
+	}
+	return converted
+}
@@ -0,0 +1,44 @@
+package kafka
+
+import (
+	"testing"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/messaging"
+	"github.com/josimarz/fc-eda-challenge/pkg/events/codec"
+	"github.com/stretchr/testify/assert"
+)
+
+type testPayload struct {
+	Id     string  `json:"id"`
+	Amount float64 `json:"amount"`
+}
+
+func newTestRegistry(t *testing.T) *codec.Registry {
+	avro, err := codec.NewAvro(map[string]string{"test.v1": `{
+		"type": "record",
+		"name": "Test",
+		"fields": [
+			{ "name": "id", "type": "string" },
+			{ "name": "amount", "type": "double" }
+		]
+	}`})
+	assert.Nil(t, err)
+	return codec.NewRegistry(codec.NewJSON(), avro)
+}
+
+func TestFromKafka(t *testing.T) {
+	topic := "test"
+	message := fromKafka(&ckafka.Message{
+		TopicPartition: ckafka.TopicPartition{Topic: &topic, Partition: 2, Offset: 10},
+		Key:            []byte("1"),
+		Value:          []byte(`{}`),
+		Headers:        []ckafka.Header{{Key: messaging.ContentTypeHeader, Value: []byte("application/json")}},
+	})
+	assert.Equal(t, "test", message.Topic)
+	assert.Equal(t, int32(2), message.Partition)
+	assert.Equal(t, int64(10), message.Offset)
+	assert.Equal(t, []byte("1"), message.Key)
+	assert.Equal(t, []messaging.Header{{Key: messaging.ContentTypeHeader, Value: []byte("application/json")}}, message.Headers)
+	assert.Equal(t, []ckafka.Header{{Key: messaging.ContentTypeHeader, Value: []byte("application/json")}}, toKafkaHeaders(message.Headers))
+}
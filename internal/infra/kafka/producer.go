@@ -0,0 +1,251 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/messaging"
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/josimarz/fc-eda-challenge/pkg/events/codec"
+)
+
+var ErrProducerClosed = errors.New("kafka producer is closed")
+
+var _ messaging.Publisher = (*Producer)(nil)
+
+const (
+	flushInterval  = 100 * time.Millisecond
+	commitAttempts = 5
+)
+
+// commitBackoff spaces the retries of a commit that failed with a retriable
+// error, so a struggling broker is not hammered.
+var commitBackoff = events.ExponentialBackoff(100*time.Millisecond, 2*time.Second)
+
+type Producer struct {
+	Source       string
+	Codecs       map[string]codec.Codec
+	producer     *ckafka.Producer
+	mu           sync.RWMutex
+	closed       bool
+	transactions sync.WaitGroup
+	done         chan struct{}
+	delivered    atomic.Int64
+	failed       atomic.Int64
+}
+
+func NewProducer(configMap *ckafka.ConfigMap, source string, codecs map[string]codec.Codec) (*Producer, error) {
+	producer, err := ckafka.NewProducer(configMap)
+	if err != nil {
+		return nil, err
+	}
+	p := &Producer{
+		Source:   source,
+		Codecs:   codecs,
+		producer: producer,
+		done:     make(chan struct{}),
+	}
+	go p.deliveryReports()
+	return p, nil
+}
+
+func NewTransactionalProducer(ctx context.Context, configMap *ckafka.ConfigMap, source string, codecs map[string]codec.Codec, transactionalId string) (*Producer, error) {
+	transactional := ckafka.ConfigMap{}
+	for key, value := range *configMap {
+		transactional[key] = value
+	}
+	transactional["enable.idempotence"] = true
+	transactional["transactional.id"] = transactionalId
+	p, err := NewProducer(&transactional, source, codecs)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.producer.InitTransactions(ctx); err != nil {
+		p.Close(ctx)
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Producer) codec(topic string) codec.Codec {
+	if c, ok := p.Codecs[topic]; ok {
+		return c
+	}
+	return codec.NewJSON()
+}
+
+func (p *Producer) Publish(ctx context.Context, event events.Event, key []byte, topic string) error {
+	message, err := p.message(ctx, event, key, topic)
+	if err != nil {
+		return err
+	}
+	return p.produce(message, nil)
+}
+
+func (p *Producer) PublishSync(ctx context.Context, event events.Event, key []byte, topic string) error {
+	message, err := p.message(ctx, event, key, topic)
+	if err != nil {
+		return err
+	}
+	return p.produceSync(ctx, message)
+}
+
+func (p *Producer) produceSync(ctx context.Context, message *ckafka.Message) error {
+	result := make(chan error, 1)
+	if err := p.produce(message, result); err != nil {
+		return err
+	}
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.done:
+		return ErrProducerClosed
+	}
+}
+
+func (p *Producer) Transact(ctx context.Context, consumer *Consumer, message *ckafka.Message, fn func(ctx context.Context) error) error {
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return ErrProducerClosed
+	}
+	p.transactions.Add(1)
+	p.mu.RUnlock()
+	defer p.transactions.Done()
+	if err := p.producer.BeginTransaction(); err != nil {
+		return err
+	}
+	if err := fn(ctx); err != nil {
+		return p.abort(ctx, err)
+	}
+	metadata, err := consumer.GroupMetadata()
+	if err != nil {
+		return p.abort(ctx, err)
+	}
+	offsets := []ckafka.TopicPartition{{
+		Topic:     message.TopicPartition.Topic,
+		Partition: message.TopicPartition.Partition,
+		Offset:    message.TopicPartition.Offset + 1,
+	}}
+	if err := p.producer.SendOffsetsToTransaction(ctx, offsets, metadata); err != nil {
+		return p.abort(ctx, err)
+	}
+	for attempt := 1; ; attempt++ {
+		err := p.producer.CommitTransaction(ctx)
+		if err == nil {
+			return nil
+		}
+		if kerr, ok := err.(ckafka.Error); !ok || !kerr.IsRetriable() || attempt >= commitAttempts {
+			return p.abort(ctx, err)
+		}
+		select {
+		case <-time.After(commitBackoff(attempt)):
+		case <-ctx.Done():
+			return p.abort(ctx, err)
+		}
+	}
+}
+
+func (p *Producer) abort(ctx context.Context, cause error) error {
+	if err := p.producer.AbortTransaction(ctx); err != nil {
+		return errors.Join(cause, fmt.Errorf("aborting transaction: %w", err))
+	}
+	return cause
+}
+
+func (p *Producer) Delivered() int64 {
+	return p.delivered.Load()
+}
+
+func (p *Producer) Failed() int64 {
+	return p.failed.Load()
+}
+
+func (p *Producer) Flush(ctx context.Context) error {
+	for {
+		remaining := p.producer.Flush(int(flushInterval / time.Millisecond))
+		if remaining == 0 {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("%d messages still pending: %w", remaining, err)
+		}
+	}
+}
+
+func (p *Producer) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	p.mu.Unlock()
+	p.transactions.Wait()
+	err := p.Flush(ctx)
+	p.producer.Close()
+	<-p.done
+	return err
+}
+
+func (p *Producer) message(ctx context.Context, event events.Event, key []byte, topic string) (*ckafka.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	envelope, err := events.NewEnvelope(ctx, p.Source, event)
+	if err != nil {
+		return nil, err
+	}
+	value, headers, err := messaging.EncodeMessage(envelope, p.codec(topic))
+	if err != nil {
+		return nil, err
+	}
+	return &ckafka.Message{
+		TopicPartition: ckafka.TopicPartition{Topic: &topic, Partition: ckafka.PartitionAny},
+		Value:          value,
+		Key:            key,
+		Headers:        toKafkaHeaders(headers),
+	}, nil
+}
+
+func (p *Producer) produce(message *ckafka.Message, result chan error) error {
+	if result != nil {
+		message.Opaque = result
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrProducerClosed
+	}
+	return p.producer.Produce(message, nil)
+}
+
+func (p *Producer) deliveryReports() {
+	defer close(p.done)
+	for e := range p.producer.Events() {
+		switch ev := e.(type) {
+		case *ckafka.Message:
+			err := ev.TopicPartition.Error
+			if err != nil {
+				p.failed.Add(1)
+			} else {
+				p.delivered.Add(1)
+			}
+			if result, ok := ev.Opaque.(chan error); ok {
+				result <- err
+			} else if err != nil {
+				log.Printf("[Kafka] delivery to %s failed: %s\n", ev.TopicPartition, err)
+			}
+		case ckafka.Error:
+			log.Printf("[Kafka] %s\n", ev)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProducerTestSuite struct {
+	suite.Suite
+	cluster  *ckafka.MockCluster
+	producer *Producer
+}
+
+func (suite *ProducerTestSuite) SetupTest() {
+	cluster, err := ckafka.NewMockCluster(1)
+	suite.Require().Nil(err)
+	suite.cluster = cluster
+	suite.producer, err = NewProducer(&ckafka.ConfigMap{"bootstrap.servers": cluster.BootstrapServers()}, "test", nil)
+	suite.Require().Nil(err)
+}
+
+func (suite *ProducerTestSuite) TearDownTest() {
+	suite.producer.Close(context.Background())
+	suite.cluster.Close()
+}
+
+func (suite *ProducerTestSuite) TestPublishSync() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := suite.producer.PublishSync(ctx, events.NewTypedEvent("test", testPayload{"1", 10}), []byte("1"), "test")
+	suite.Nil(err)
+	suite.Equal(int64(1), suite.producer.Delivered())
+}
+
+func (suite *ProducerTestSuite) TestPublish_WithFlush() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for i := 0; i < 5; i++ {
+		suite.Nil(suite.producer.Publish(ctx, events.NewTypedEvent("test", testPayload{"1", 10}), nil, "test"))
+	}
+	suite.Nil(suite.producer.Flush(ctx))
+	suite.Eventually(func() bool { return suite.producer.Delivered() == 5 }, time.Second, 10*time.Millisecond)
+	suite.Zero(suite.producer.Failed())
+}
+
+func (suite *ProducerTestSuite) TestPublish_AfterClose() {
+	suite.Nil(suite.producer.Close(context.Background()))
+	suite.Nil(suite.producer.Close(context.Background()))
+	err := suite.producer.PublishSync(context.Background(), events.NewTypedEvent("test", testPayload{"1", 10}), nil, "test")
+	suite.ErrorIs(err, ErrProducerClosed)
+}
+
+func (suite *ProducerTestSuite) TestPublishSync_WithCanceledContext() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := suite.producer.PublishSync(ctx, events.NewTypedEvent("test", testPayload{"1", 10}), nil, "test")
+	suite.ErrorIs(err, context.Canceled)
+}
+
+func TestProducerTestSuite(t *testing.T) {
+	suite.Run(t, new(ProducerTestSuite))
+}
@@ -0,0 +1,111 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+const (
+	retryPrefix          = "retry_"
+	retryAttemptHeader   = "retry_attempt"
+	retryDueHeader       = "retry_due"
+	retryErrorHeader     = "retry_error"
+	retryTopicHeader     = "retry_topic"
+	retryPartitionHeader = "retry_partition"
+	retryOffsetHeader    = "retry_offset"
+)
+
+var DefaultRetryTiers = []time.Duration{5 * time.Second, time.Minute, 10 * time.Minute}
+
+func RetryTopic(topic string, delay time.Duration) string {
+	return topic + ".retry." + delayLabel(delay)
+}
+
+func RetryTopics(topic string, tiers []time.Duration) []string {
+	topics := make([]string, 0, len(tiers))
+	for _, delay := range tiers {
+		topics = append(topics, RetryTopic(topic, delay))
+	}
+	return topics
+}
+
+func (p *Producer) Retry(ctx context.Context, message *ckafka.Message, cause error, tiers []time.Duration) error {
+	attempt := RetryAttempt(message)
+	if attempt >= len(tiers) {
+		return p.DeadLetter(ctx, message, cause, attempt+1)
+	}
+	origin := originOf(message)
+	topic := RetryTopic(*origin.Topic, tiers[attempt])
+	headers := append(withoutHeaders(message.Headers, retryPrefix),
+		ckafka.Header{Key: retryAttemptHeader, Value: []byte(strconv.Itoa(attempt + 1))},
+		ckafka.Header{Key: retryDueHeader, Value: []byte(time.Now().Add(tiers[attempt]).UTC().Format(time.RFC3339Nano))},
+		ckafka.Header{Key: retryErrorHeader, Value: []byte(cause.Error())},
+		ckafka.Header{Key: retryTopicHeader, Value: []byte(*origin.Topic)},
+		ckafka.Header{Key: retryPartitionHeader, Value: []byte(strconv.Itoa(int(origin.Partition)))},
+		ckafka.Header{Key: retryOffsetHeader, Value: []byte(strconv.FormatInt(int64(origin.Offset), 10))},
+	)
+	return p.produceSync(ctx, &ckafka.Message{
+		TopicPartition: ckafka.TopicPartition{Topic: &topic, Partition: ckafka.PartitionAny},
+		Value:          message.Value,
+		Key:            message.Key,
+		Headers:        headers,
+	})
+}
+
+func RetryAttempt(message *ckafka.Message) int {
+	attempt, err := strconv.Atoi(header(message, retryAttemptHeader))
+	if err != nil {
+		return 0
+	}
+	return attempt
+}
+
+func RetryDue(message *ckafka.Message) time.Time {
+	due, err := time.Parse(time.RFC3339Nano, header(message, retryDueHeader))
+	if err != nil {
+		return time.Time{}
+	}
+	return due
+}
+
+func originOf(message *ckafka.Message) ckafka.TopicPartition {
+	topic := header(message, retryTopicHeader)
+	if topic == "" {
+		return message.TopicPartition
+	}
+	partition, err := strconv.ParseInt(header(message, retryPartitionHeader), 10, 32)
+	if err != nil {
+		return message.TopicPartition
+	}
+	offset, err := strconv.ParseInt(header(message, retryOffsetHeader), 10, 64)
+	if err != nil {
+		return message.TopicPartition
+	}
+	return ckafka.TopicPartition{Topic: &topic, Partition: int32(partition), Offset: ckafka.Offset(offset)}
+}
+
+func header(message *ckafka.Message, key string) string {
+	for _, header := range message.Headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+func delayLabel(delay time.Duration) string {
+	switch {
+	case delay%time.Hour == 0:
+		return fmt.Sprintf("%dh", delay/time.Hour)
+	case delay%time.Minute == 0:
+		return fmt.Sprintf("%dm", delay/time.Minute)
+	case delay%time.Second == 0:
+		return fmt.Sprintf("%ds", delay/time.Second)
+	default:
+		return fmt.Sprintf("%dms", delay/time.Millisecond)
+	}
+}
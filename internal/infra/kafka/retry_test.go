@@ -0,0 +1,93 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/messaging"
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryTopic(t *testing.T) {
+	assert.Equal(t, "transactions.retry.5s", RetryTopic("transactions", 5*time.Second))
+	assert.Equal(t, "transactions.retry.1m", RetryTopic("transactions", time.Minute))
+	assert.Equal(t, "transactions.retry.10m", RetryTopic("transactions", 10*time.Minute))
+	assert.Equal(t, "transactions.retry.250ms", RetryTopic("transactions", 250*time.Millisecond))
+	assert.Equal(t, []string{"transactions.retry.5s", "transactions.retry.1m", "transactions.retry.10m"}, RetryTopics("transactions", DefaultRetryTiers))
+}
+
+func (suite *ConsumerTestSuite) TestRun_WithRetryTopics() {
+	suite.publish("1", "2", "3")
+	producer, err := NewProducer(&ckafka.ConfigMap{"bootstrap.servers": suite.cluster.BootstrapServers()}, "test", nil)
+	suite.Require().Nil(err)
+	defer producer.Close(suite.ctx)
+	tiers := []time.Duration{500 * time.Millisecond, time.Second}
+	consumer := suite.newConsumer(WithRetryTopics(producer, tiers...))
+	defer consumer.Close()
+
+	ctx, cancel := context.WithCancel(suite.ctx)
+	defer cancel()
+	var mu sync.Mutex
+	var ids []string
+	calls := make(map[string]time.Time)
+	var delays []time.Duration
+	done := make(chan error)
+	go func() {
+		done <- consumer.Run(ctx, func(ctx context.Context, message *messaging.Message) error {
+			envelope, err := messaging.DecodeMessage(message, newTestRegistry(suite.T()))
+			if err != nil {
+				return err
+			}
+			payload := testPayload{}
+			if err := envelope.Decode(&payload); err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			ids = append(ids, payload.Id)
+			if last, ok := calls[payload.Id]; ok && payload.Id == "1" {
+				delays = append(delays, time.Since(last))
+			}
+			calls[payload.Id] = time.Now()
+			switch payload.Id {
+			case "1":
+				return errors.New("database unavailable")
+			case "2":
+				return events.Permanent(errors.New("malformed payload"))
+			}
+			return nil
+		})
+	}()
+
+	configMap := &ckafka.ConfigMap{"bootstrap.servers": suite.cluster.BootstrapServers()}
+	var letters []*DeadLetter
+	suite.Eventually(func() bool {
+		letters, err = ReadDeadLetters(suite.ctx, configMap, "test")
+		return err == nil && len(letters) == 2
+	}, 20*time.Second, 200*time.Millisecond)
+	cancel()
+	suite.Nil(<-done)
+
+	mu.Lock()
+	defer mu.Unlock()
+	suite.Equal([]string{"1", "2", "3", "1", "1"}, ids)
+	suite.Require().Len(delays, 2)
+	suite.GreaterOrEqual(delays[0], 400*time.Millisecond)
+	suite.GreaterOrEqual(delays[1], 900*time.Millisecond)
+
+	suite.Require().Len(letters, 2)
+	suite.Equal("test", letters[0].Topic)
+	suite.Equal(int64(1), letters[0].Offset)
+	suite.Equal("malformed payload", letters[0].Error)
+	suite.Equal(1, letters[0].Attempts)
+	suite.Equal("test", letters[1].Topic)
+	suite.Equal(int64(0), letters[1].Offset)
+	suite.Equal("database unavailable", letters[1].Error)
+	suite.Equal(3, letters[1].Attempts)
+	suite.Equal(2, RetryAttempt(letters[1].Message))
+}
@@ -0,0 +1,386 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"gopkg.in/yaml.v3"
+)
+
+const topicsTimeout = 60 * time.Second
+
+var (
+	ErrInvalidTopic    = errors.New("invalid topic definition")
+	ErrPartitionShrink = errors.New("cannot reduce the number of partitions")
+)
+
+type TopicSpec struct {
+	Name              string            `yaml:"name"`
+	Partitions        int               `yaml:"partitions"`
+	ReplicationFactor int               `yaml:"replicationFactor"`
+	Retention         time.Duration     `yaml:"retention"`
+	CleanupPolicy     string            `yaml:"cleanupPolicy"`
+	Retry             bool              `yaml:"retry"`
+	Configs           map[string]string `yaml:"configs"`
+}
+
+type topicsFile struct {
+	Defaults TopicSpec   `yaml:"defaults"`
+	Topics   []TopicSpec `yaml:"topics"`
+}
+
+func LoadTopics(path string) ([]TopicSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTopics(data)
+}
+
+func ParseTopics(data []byte) ([]TopicSpec, error) {
+	var file topicsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	var topics []TopicSpec
+	declared := make(map[string]bool)
+	for _, topic := range file.Topics {
+		topic = topic.withDefaults(file.Defaults)
+		expanded := []TopicSpec{topic}
+		if topic.Retry {
+			for _, name := range append(RetryTopics(topic.Name, DefaultRetryTiers), DeadLetterTopic(topic.Name)) {
+				derived := topic
+				derived.Name = name
+				derived.Retry = false
+				expanded = append(expanded, derived)
+			}
+		}
+		for _, spec := range expanded {
+			if err := spec.validate(); err != nil {
+				return nil, err
+			}
+			if declared[spec.Name] {
+				return nil, fmt.Errorf("%w: %s is declared twice", ErrInvalidTopic, spec.Name)
+			}
+			declared[spec.Name] = true
+			topics = append(topics, spec)
+		}
+	}
+	return topics, nil
+}
+
+func (t TopicSpec) Config() map[string]string {
+	config := make(map[string]string, len(t.Configs)+2)
+	for key, value := range t.Configs {
+		config[key] = value
+	}
+	if t.Retention != 0 {
+		config["retention.ms"] = strconv.FormatInt(t.Retention.Milliseconds(), 10)
+	}
+	if t.CleanupPolicy != "" {
+		config["cleanup.policy"] = t.CleanupPolicy
+	}
+	return config
+}
+
+func (t TopicSpec) withDefaults(defaults TopicSpec) TopicSpec {
+	if t.Partitions == 0 {
+		t.Partitions = defaults.Partitions
+	}
+	if t.ReplicationFactor == 0 {
+		t.ReplicationFactor = defaults.ReplicationFactor
+	}
+	if t.Retention == 0 {
+		t.Retention = defaults.Retention
+	}
+	if t.CleanupPolicy == "" {
+		t.CleanupPolicy = defaults.CleanupPolicy
+	}
+	configs := make(map[string]string, len(defaults.Configs)+len(t.Configs))
+	for key, value := range defaults.Configs {
+		configs[key] = value
+	}
+	for key, value := range t.Configs {
+		configs[key] = value
+	}
+	t.Configs = configs
+	return t
+}
+
+func (t TopicSpec) validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("%w: missing name", ErrInvalidTopic)
+	}
+	if t.Partitions < 1 {
+		return fmt.Errorf("%w: %s must have at least one partition", ErrInvalidTopic, t.Name)
+	}
+	if t.ReplicationFactor < 1 {
+		return fmt.Errorf("%w: %s must have a replication factor of at least one", ErrInvalidTopic, t.Name)
+	}
+	return nil
+}
+
+type TopicChangeKind string
+
+const (
+	CreateTopic   TopicChangeKind = "create"
+	AddPartitions TopicChangeKind = "partitions"
+	UpdateConfig  TopicChangeKind = "config"
+)
+
+type TopicChange struct {
+	Kind  TopicChangeKind
+	Topic string
+	Key   string
+	From  string
+	To    string
+}
+
+func (c TopicChange) String() string {
+	switch c.Kind {
+	case CreateTopic:
+		return fmt.Sprintf("+ %s (%s)", c.Topic, c.To)
+	case AddPartitions:
+		return fmt.Sprintf("~ %s partitions: %s -> %s", c.Topic, c.From, c.To)
+	default:
+		return fmt.Sprintf("~ %s %s: %q -> %q", c.Topic, c.Key, c.From, c.To)
+	}
+}
+
+type TopicState struct {
+	Partitions int
+	Config     map[string]string
+	Overrides  map[string]string
+}
+
+type TopicPlan struct {
+	Changes    []TopicChange
+	create     []ckafka.TopicSpecification
+	partitions []ckafka.PartitionsSpecification
+	configs    []ckafka.ConfigResource
+}
+
+func (p *TopicPlan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+type TopicManager struct {
+	admin *ckafka.AdminClient
+}
+
+func NewTopicManager(configMap *ckafka.ConfigMap) (*TopicManager, error) {
+	admin, err := ckafka.NewAdminClient(configMap)
+	if err != nil {
+		return nil, err
+	}
+	return &TopicManager{admin}, nil
+}
+
+func (m *TopicManager) Plan(ctx context.Context, topics []TopicSpec) (*TopicPlan, error) {
+	current, err := m.describe(ctx, topics)
+	if err != nil {
+		return nil, err
+	}
+	return diffTopics(topics, current)
+}
+
+func (m *TopicManager) Apply(ctx context.Context, plan *TopicPlan) error {
+	if len(plan.create) > 0 {
+		results, err := m.admin.CreateTopics(ctx, plan.create, ckafka.SetAdminOperationTimeout(topicsTimeout))
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			if code := result.Error.Code(); code != ckafka.ErrNoError && code != ckafka.ErrTopicAlreadyExists {
+				return fmt.Errorf("creating topic %s: %w", result.Topic, result.Error)
+			}
+		}
+	}
+	if len(plan.partitions) > 0 {
+		results, err := m.admin.CreatePartitions(ctx, plan.partitions, ckafka.SetAdminOperationTimeout(topicsTimeout))
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			if result.Error.Code() != ckafka.ErrNoError {
+				return fmt.Errorf("adding partitions to %s: %w", result.Topic, result.Error)
+			}
+		}
+	}
+	if len(plan.configs) > 0 {
+		results, err := m.admin.AlterConfigs(ctx, plan.configs)
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			if result.Error.Code() != ckafka.ErrNoError {
+				return fmt.Errorf("updating configs of %s: %w", result.Name, result.Error)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *TopicManager) Close() {
+	m.admin.Close()
+}
+
+func (m *TopicManager) describe(ctx context.Context, topics []TopicSpec) (map[string]TopicState, error) {
+	timeout := topicsTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	metadata, err := m.admin.GetMetadata(nil, true, int(timeout.Milliseconds()))
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]TopicState)
+	var resources []ckafka.ConfigResource
+	for _, topic := range topics {
+		md, ok := metadata.Topics[topic.Name]
+		if !ok || md.Error.Code() == ckafka.ErrUnknownTopicOrPart {
+			continue
+		}
+		if md.Error.Code() != ckafka.ErrNoError {
+			return nil, fmt.Errorf("describing topic %s: %w", topic.Name, md.Error)
+		}
+		current[topic.Name] = TopicState{
+			Partitions: len(md.Partitions),
+			Config:     make(map[string]string),
+			Overrides:  make(map[string]string),
+		}
+		resources = append(resources, ckafka.ConfigResource{Type: ckafka.ResourceTopic, Name: topic.Name})
+	}
+	if len(resources) == 0 {
+		return current, nil
+	}
+	results, err := m.admin.DescribeConfigs(ctx, resources)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		if result.Error.Code() != ckafka.ErrNoError {
+			return nil, fmt.Errorf("describing configs of %s: %w", result.Name, result.Error)
+		}
+		state := current[result.Name]
+		for name, entry := range result.Config {
+			state.Config[name] = entry.Value
+			if entry.Source == ckafka.ConfigSourceDynamicTopic {
+				state.Overrides[name] = entry.Value
+			}
+		}
+	}
+	return current, nil
+}
+
+// EnsureTopics creates the declared topics that do not exist yet. Adding
+// partitions or changing the configs of existing topics is left to the topics
+// command, so a service starting with an outdated TOPICS_PATH never alters them.
+func EnsureTopics(ctx context.Context, configMap *ckafka.ConfigMap, topics []TopicSpec) ([]TopicChange, error) {
+	manager, err := NewTopicManager(configMap)
+	if err != nil {
+		return nil, err
+	}
+	defer manager.Close()
+	current, err := manager.describe(ctx, topics)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := diffTopics(missingTopics(topics, current), current)
+	if err != nil {
+		return nil, err
+	}
+	if err := manager.Apply(ctx, plan); err != nil {
+		return nil, err
+	}
+	return plan.Changes, nil
+}
+
+func missingTopics(topics []TopicSpec, current map[string]TopicState) []TopicSpec {
+	var missing []TopicSpec
+	for _, topic := range topics {
+		if _, ok := current[topic.Name]; !ok {
+			missing = append(missing, topic)
+		}
+	}
+	return missing
+}
+
+func diffTopics(topics []TopicSpec, current map[string]TopicState) (*TopicPlan, error) {
+	plan := &TopicPlan{}
+	for _, topic := range topics {
+		desired := topic.Config()
+		state, ok := current[topic.Name]
+		if !ok {
+			plan.create = append(plan.create, ckafka.TopicSpecification{
+				Topic:             topic.Name,
+				NumPartitions:     topic.Partitions,
+				ReplicationFactor: topic.ReplicationFactor,
+				Config:            desired,
+			})
+			plan.Changes = append(plan.Changes, TopicChange{Kind: CreateTopic, Topic: topic.Name, To: summarize(topic, desired)})
+			continue
+		}
+		if topic.Partitions < state.Partitions {
+			return nil, fmt.Errorf("%w: %s has %d partitions, %d declared", ErrPartitionShrink, topic.Name, state.Partitions, topic.Partitions)
+		}
+		if topic.Partitions > state.Partitions {
+			plan.partitions = append(plan.partitions, ckafka.PartitionsSpecification{Topic: topic.Name, IncreaseTo: topic.Partitions})
+			plan.Changes = append(plan.Changes, TopicChange{
+				Kind:  AddPartitions,
+				Topic: topic.Name,
+				From:  strconv.Itoa(state.Partitions),
+				To:    strconv.Itoa(topic.Partitions),
+			})
+		}
+		changed := false
+		for _, key := range sortedKeys(desired) {
+			if state.Config[key] == desired[key] {
+				continue
+			}
+			changed = true
+			plan.Changes = append(plan.Changes, TopicChange{Kind: UpdateConfig, Topic: topic.Name, Key: key, From: state.Config[key], To: desired[key]})
+		}
+		if !changed {
+			continue
+		}
+		merged := make(map[string]string, len(state.Overrides)+len(desired))
+		for key, value := range state.Overrides {
+			merged[key] = value
+		}
+		for key, value := range desired {
+			merged[key] = value
+		}
+		entries := ckafka.StringMapToConfigEntries(merged, ckafka.AlterOperationSet)
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+		plan.configs = append(plan.configs, ckafka.ConfigResource{Type: ckafka.ResourceTopic, Name: topic.Name, Config: entries})
+	}
+	return plan, nil
+}
+
+func summarize(topic TopicSpec, config map[string]string) string {
+	parts := []string{
+		fmt.Sprintf("partitions=%d", topic.Partitions),
+		fmt.Sprintf("replication=%d", topic.ReplicationFactor),
+	}
+	for _, key := range sortedKeys(config) {
+		parts = append(parts, key+"="+config[key])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
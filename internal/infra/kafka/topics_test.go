@@ -0,0 +1,143 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/stretchr/testify/assert"
+)
+
+const testTopics = `
+defaults:
+  partitions: 3
+  replicationFactor: 1
+  retention: 168h
+topics:
+  - name: transactions
+    retry: true
+  - name: balances
+    partitions: 6
+    cleanupPolicy: compact
+    configs:
+      min.insync.replicas: "1"
+`
+
+func TestParseTopics(t *testing.T) {
+	topics, err := ParseTopics([]byte(testTopics))
+	assert.Nil(t, err)
+	var names []string
+	for _, topic := range topics {
+		names = append(names, topic.Name)
+	}
+	assert.Equal(t, []string{
+		"transactions",
+		"transactions.retry.5s",
+		"transactions.retry.1m",
+		"transactions.retry.10m",
+		"transactions.dlq",
+		"balances",
+	}, names)
+	assert.Equal(t, 3, topics[4].Partitions)
+	assert.Equal(t, 168*time.Hour, topics[4].Retention)
+	assert.Equal(t, map[string]string{"retention.ms": "604800000"}, topics[0].Config())
+	assert.Equal(t, 6, topics[5].Partitions)
+	assert.Equal(t, 1, topics[5].ReplicationFactor)
+	assert.Equal(t, map[string]string{
+		"retention.ms":        "604800000",
+		"cleanup.policy":      "compact",
+		"min.insync.replicas": "1",
+	}, topics[5].Config())
+}
+
+func TestParseTopics_WithInvalidTopic(t *testing.T) {
+	_, err := ParseTopics([]byte("topics:\n  - name: transactions\n"))
+	assert.ErrorIs(t, err, ErrInvalidTopic)
+	_, err = ParseTopics([]byte("defaults:\n  partitions: 1\n  replicationFactor: 1\ntopics:\n  - partitions: 1\n"))
+	assert.ErrorIs(t, err, ErrInvalidTopic)
+	_, err = ParseTopics([]byte("defaults:\n  partitions: 1\n  replicationFactor: 1\ntopics:\n  - name: transactions\n    retry: true\n  - name: transactions.dlq\n"))
+	assert.ErrorIs(t, err, ErrInvalidTopic)
+}
+
+func TestDiffTopics(t *testing.T) {
+	topics := []TopicSpec{
+		{Name: "transactions", Partitions: 3, ReplicationFactor: 1, Retention: time.Hour},
+		{Name: "balances", Partitions: 6, ReplicationFactor: 1, CleanupPolicy: "compact"},
+		{Name: "payment_requests", Partitions: 3, ReplicationFactor: 1, Retention: time.Hour},
+	}
+	current := map[string]TopicState{
+		"balances": {
+			Partitions: 3,
+			Config:     map[string]string{"cleanup.policy": "delete", "retention.ms": "1000", "segment.ms": "60000"},
+			Overrides:  map[string]string{"retention.ms": "1000"},
+		},
+		"payment_requests": {
+			Partitions: 3,
+			Config:     map[string]string{"cleanup.policy": "delete", "retention.ms": "3600000"},
+			Overrides:  map[string]string{"retention.ms": "3600000"},
+		},
+	}
+
+	plan, err := diffTopics(topics, current)
+	assert.Nil(t, err)
+	assert.False(t, plan.Empty())
+	assert.Equal(t, []TopicChange{
+		{Kind: CreateTopic, Topic: "transactions", To: "partitions=3, replication=1, retention.ms=3600000"},
+		{Kind: AddPartitions, Topic: "balances", From: "3", To: "6"},
+		{Kind: UpdateConfig, Topic: "balances", Key: "cleanup.policy", From: "delete", To: "compact"},
+	}, plan.Changes)
+	assert.Equal(t, "+ transactions (partitions=3, replication=1, retention.ms=3600000)", plan.Changes[0].String())
+	assert.Equal(t, "~ balances partitions: 3 -> 6", plan.Changes[1].String())
+	assert.Equal(t, `~ balances cleanup.policy: "delete" -> "compact"`, plan.Changes[2].String())
+
+	assert.Equal(t, []ckafka.TopicSpecification{{
+		Topic:             "transactions",
+		NumPartitions:     3,
+		ReplicationFactor: 1,
+		Config:            map[string]string{"retention.ms": "3600000"},
+	}}, plan.create)
+	assert.Equal(t, []ckafka.PartitionsSpecification{{Topic: "balances", IncreaseTo: 6}}, plan.partitions)
+	assert.Equal(t, []ckafka.ConfigResource{{
+		Type: ckafka.ResourceTopic,
+		Name: "balances",
+		Config: []ckafka.ConfigEntry{
+			{Name: "cleanup.policy", Value: "compact", Operation: ckafka.AlterOperationSet},
+			{Name: "retention.ms", Value: "1000", Operation: ckafka.AlterOperationSet},
+		},
+	}}, plan.configs)
+}
+
+func TestDiffTopics_WithoutChanges(t *testing.T) {
+	topics := []TopicSpec{{Name: "transactions", Partitions: 3, ReplicationFactor: 1, CleanupPolicy: "delete"}}
+	current := map[string]TopicState{
+		"transactions": {Partitions: 3, Config: map[string]string{"cleanup.policy": "delete"}},
+	}
+
+	plan, err := diffTopics(topics, current)
+	assert.Nil(t, err)
+	assert.True(t, plan.Empty())
+	assert.Empty(t, plan.configs)
+}
+
+func TestDiffTopics_WithFewerPartitions(t *testing.T) {
+	topics := []TopicSpec{{Name: "transactions", Partitions: 1, ReplicationFactor: 1}}
+	current := map[string]TopicState{"transactions": {Partitions: 3}}
+
+	_, err := diffTopics(topics, current)
+	assert.ErrorIs(t, err, ErrPartitionShrink)
+}
+
+func TestMissingTopics(t *testing.T) {
+	topics := []TopicSpec{
+		{Name: "transactions", Partitions: 3, ReplicationFactor: 1},
+		{Name: "balances", Partitions: 6, ReplicationFactor: 1},
+	}
+	current := map[string]TopicState{"balances": {Partitions: 3}}
+
+	plan, err := diffTopics(missingTopics(topics, current), current)
+	assert.Nil(t, err)
+	assert.Equal(t, []TopicChange{
+		{Kind: CreateTopic, Topic: "transactions", To: "partitions=3, replication=1"},
+	}, plan.Changes)
+	assert.Empty(t, plan.partitions)
+}
@@ -0,0 +1,169 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/messaging"
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/stretchr/testify/suite"
+)
+
+type TransactionTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	cancel   context.CancelFunc
+	cluster  *ckafka.MockCluster
+	producer *Producer
+	consumer *Consumer
+}
+
+func (suite *TransactionTestSuite) SetupTest() {
+	suite.ctx, suite.cancel = context.WithTimeout(context.Background(), 30*time.Second)
+	cluster, err := ckafka.NewMockCluster(1)
+	suite.Require().Nil(err)
+	suite.cluster = cluster
+	configMap := &ckafka.ConfigMap{"bootstrap.servers": cluster.BootstrapServers()}
+	suite.producer, err = NewTransactionalProducer(suite.ctx, configMap, "test", nil, "test-transactional")
+	suite.Require().Nil(err)
+	suite.consumer = suite.newConsumer("in", "group")
+	suite.publish("in")
+}
+
+func (suite *TransactionTestSuite) TearDownTest() {
+	suite.consumer.Close()
+	suite.producer.Close(suite.ctx)
+	suite.cluster.Close()
+	suite.cancel()
+}
+
+func (suite *TransactionTestSuite) newConsumer(topic, group string) *Consumer {
+	consumer, err := NewConsumer(&ckafka.ConfigMap{
+		"bootstrap.servers":  suite.cluster.BootstrapServers(),
+		"group.id":           group,
+		"auto.offset.reset":  "earliest",
+		"enable.auto.commit": false,
+		"isolation.level":    "read_committed",
+	}, []string{topic})
+	suite.Require().Nil(err)
+	return consumer
+}
+
+func (suite *TransactionTestSuite) publish(topic string) {
+	producer, err := NewProducer(&ckafka.ConfigMap{"bootstrap.servers": suite.cluster.BootstrapServers()}, "test", nil)
+	suite.Require().Nil(err)
+	defer producer.Close(suite.ctx)
+	suite.Require().Nil(producer.PublishSync(suite.ctx, events.NewTypedEvent("test", testPayload{"1", 10}), nil, topic))
+}
+
+func (suite *TransactionTestSuite) TestTransact() {
+	message, err := suite.consumer.Next(suite.ctx)
+	suite.Require().Nil(err)
+	err = suite.producer.Transact(suite.ctx, suite.consumer, message, func(ctx context.Context) error {
+		return suite.producer.PublishSync(ctx, events.NewTypedEvent("test", testPayload{"2", 20}), nil, "out")
+	})
+	suite.Nil(err)
+
+	out := suite.newConsumer("out", "reader")
+	defer out.Close()
+	received, err := out.Next(suite.ctx)
+	suite.Require().Nil(err)
+	envelope, err := messaging.DecodeMessage(fromKafka(received), newTestRegistry(suite.T()))
+	suite.Nil(err)
+	suite.Equal("test", envelope.Type)
+}
+
+func (suite *TransactionTestSuite) TestTransact_WithFailure() {
+	message, err := suite.consumer.Next(suite.ctx)
+	suite.Require().Nil(err)
+	err = suite.producer.Transact(suite.ctx, suite.consumer, message, func(ctx context.Context) error {
+		if err := suite.producer.PublishSync(ctx, events.NewTypedEvent("test", testPayload{"2", 20}), nil, "out"); err != nil {
+			return err
+		}
+		return errors.New("database unavailable")
+	})
+	suite.EqualError(err, "database unavailable")
+
+	out := suite.newConsumer("out", "reader")
+	defer out.Close()
+	ctx, cancel := context.WithTimeout(suite.ctx, 2*time.Second)
+	defer cancel()
+	_, err = out.Next(ctx)
+	suite.ErrorIs(err, context.DeadlineExceeded)
+
+	suite.Nil(suite.consumer.Rewind(message))
+	again, err := suite.consumer.Next(suite.ctx)
+	suite.Require().Nil(err)
+	suite.Equal(message.TopicPartition.Offset, again.TopicPartition.Offset)
+}
+
+func (suite *TransactionTestSuite) TestRun_WithTransactionsAndRetryTopics() {
+	consumer, err := NewConsumer(&ckafka.ConfigMap{
+		"bootstrap.servers":  suite.cluster.BootstrapServers(),
+		"group.id":           "retrying",
+		"auto.offset.reset":  "earliest",
+		"session.timeout.ms": 6000,
+		"enable.auto.commit": false,
+		"isolation.level":    "read_committed",
+	}, []string{"in"}, WithTransactions(suite.producer), WithRetryTopics(suite.producer, 100*time.Millisecond))
+	suite.Require().Nil(err)
+	defer consumer.Close()
+
+	ctx, cancel := context.WithCancel(suite.ctx)
+	defer cancel()
+	calls := 0
+	done := make(chan error)
+	go func() {
+		done <- consumer.Run(ctx, func(ctx context.Context, message *messaging.Message) error {
+			calls++
+			if err := suite.producer.PublishSync(ctx, events.NewTypedEvent("test", testPayload{"2", 20}), nil, "out"); err != nil {
+				return err
+			}
+			if calls == 1 {
+				return errors.New("database unavailable")
+			}
+			cancel()
+			return nil
+		})
+	}()
+	suite.Nil(<-done)
+	suite.Equal(2, calls)
+
+	retries := suite.newConsumer(RetryTopic("in", 100*time.Millisecond), "reader")
+	defer retries.Close()
+	retried, err := retries.Next(suite.ctx)
+	suite.Require().Nil(err)
+	suite.Equal(1, RetryAttempt(retried))
+	suite.Equal("database unavailable", header(retried, retryErrorHeader))
+	next, cancelNext := context.WithTimeout(suite.ctx, 2*time.Second)
+	defer cancelNext()
+	_, err = retries.Next(next)
+	suite.ErrorIs(err, context.DeadlineExceeded)
+}
+
+// The mock cluster does not append commit markers, so this only guards the
+// transactional write path; against a real broker the last offset of the
+// dead letter partition is the marker and is never delivered.
+func (suite *TransactionTestSuite) TestReadDeadLetters_WithTransactionalDeadLetter() {
+	message, err := suite.consumer.Next(suite.ctx)
+	suite.Require().Nil(err)
+	err = suite.producer.Transact(suite.ctx, suite.consumer, message, func(ctx context.Context) error {
+		return suite.producer.DeadLetter(ctx, message, errors.New("malformed payload"), 1)
+	})
+	suite.Require().Nil(err)
+
+	ctx, cancel := context.WithTimeout(suite.ctx, 10*time.Second)
+	defer cancel()
+	letters, err := ReadDeadLetters(ctx, &ckafka.ConfigMap{"bootstrap.servers": suite.cluster.BootstrapServers()}, "in")
+	suite.Require().Nil(err)
+	suite.Require().Len(letters, 1)
+	suite.Equal("in", letters[0].Topic)
+	suite.Equal("malformed payload", letters[0].Error)
+}
+
+func TestTransactionTestSuite(t *testing.T) {
+	suite.Run(t, new(TransactionTestSuite))
+}
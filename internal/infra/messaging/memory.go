@@ -0,0 +1,280 @@
+package messaging
+
+import (
+	"context"
+	"hash/fnv"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/josimarz/fc-eda-challenge/pkg/events/codec"
+)
+
+var (
+	_ Publisher  = (*MemoryPublisher)(nil)
+	_ Subscriber = (*MemorySubscriber)(nil)
+)
+
+type memoryPartition struct {
+	topic     string
+	partition int32
+}
+
+type memoryGroup struct {
+	offsets map[memoryPartition]int64
+	busy    map[memoryPartition]bool
+}
+
+type MemoryBroker struct {
+	mu         sync.Mutex
+	partitions int
+	topics     map[string][][]*Message
+	groups     map[string]*memoryGroup
+	changed    chan struct{}
+	next       int
+}
+
+func NewMemoryBroker(partitions int) *MemoryBroker {
+	if partitions < 1 {
+		partitions = 1
+	}
+	return &MemoryBroker{
+		partitions: partitions,
+		topics:     make(map[string][][]*Message),
+		groups:     make(map[string]*memoryGroup),
+		changed:    make(chan struct{}),
+	}
+}
+
+func (b *MemoryBroker) Publisher(source string, codecs map[string]codec.Codec) *MemoryPublisher {
+	return &MemoryPublisher{b, source, codecs}
+}
+
+func (b *MemoryBroker) Subscribe(group string, topics []string, opts ...SubscriberOption) *MemorySubscriber {
+	s := &MemorySubscriber{
+		broker:  b,
+		group:   group,
+		topics:  topics,
+		options: defaultSubscriberOptions(),
+	}
+	for _, opt := range opts {
+		opt(&s.options)
+	}
+	return s
+}
+
+func (b *MemoryBroker) Messages(topic string) []*Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var messages []*Message
+	for _, partition := range b.topics[topic] {
+		messages = append(messages, partition...)
+	}
+	return messages
+}
+
+func (b *MemoryBroker) Committed(group, topic string, partition int32) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.group(group).offsets[memoryPartition{topic, partition}]
+}
+
+func (b *MemoryBroker) Lag(group, topic string) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var lag int64
+	for partition, messages := range b.topics[topic] {
+		lag += int64(len(messages)) - b.group(group).offsets[memoryPartition{topic, int32(partition)}]
+	}
+	return lag
+}
+
+func (b *MemoryBroker) append(topic string, key, value []byte, headers []Header) *Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	partitions, ok := b.topics[topic]
+	if !ok {
+		partitions = make([][]*Message, b.partitions)
+		b.topics[topic] = partitions
+	}
+	partition := b.partition(key)
+	message := &Message{
+		Topic:     topic,
+		Partition: partition,
+		Offset:    int64(len(partitions[partition])),
+		Key:       key,
+		Value:     value,
+		Headers:   headers,
+		Timestamp: time.Now(),
+	}
+	partitions[partition] = append(partitions[partition], message)
+	b.notify()
+	return message
+}
+
+func (b *MemoryBroker) partition(key []byte) int32 {
+	if key == nil {
+		b.next++
+		return int32(b.next % b.partitions)
+	}
+	h := fnv.New32a()
+	h.Write(key)
+	return int32(h.Sum32() % uint32(b.partitions))
+}
+
+func (b *MemoryBroker) claim(group string, topics []string, cursor int) (*Message, <-chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	g := b.group(group)
+	var candidates []memoryPartition
+	for _, topic := range topics {
+		for partition := range b.topics[topic] {
+			candidates = append(candidates, memoryPartition{topic, int32(partition)})
+		}
+	}
+	for i := range candidates {
+		key := candidates[(cursor+i)%len(candidates)]
+		messages := b.topics[key.topic][key.partition]
+		offset := g.offsets[key]
+		if g.busy[key] || offset >= int64(len(messages)) {
+			continue
+		}
+		g.busy[key] = true
+		return messages[offset], nil
+	}
+	return nil, b.changed
+}
+
+func (b *MemoryBroker) release(group string, message *Message, commit bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	g := b.group(group)
+	key := memoryPartition{message.Topic, message.Partition}
+	delete(g.busy, key)
+	if commit {
+		g.offsets[key] = message.Offset + 1
+	}
+	b.notify()
+}
+
+func (b *MemoryBroker) group(name string) *memoryGroup {
+	g, ok := b.groups[name]
+	if !ok {
+		g = &memoryGroup{
+			offsets: make(map[memoryPartition]int64),
+			busy:    make(map[memoryPartition]bool),
+		}
+		b.groups[name] = g
+	}
+	return g
+}
+
+func (b *MemoryBroker) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+type MemoryPublisher struct {
+	broker *MemoryBroker
+	Source string
+	Codecs map[string]codec.Codec
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, event events.Event, key []byte, topic string) error {
+	return p.PublishSync(ctx, event, key, topic)
+}
+
+func (p *MemoryPublisher) PublishSync(ctx context.Context, event events.Event, key []byte, topic string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	envelope, err := events.NewEnvelope(ctx, p.Source, event)
+	if err != nil {
+		return err
+	}
+	c, ok := p.Codecs[topic]
+	if !ok {
+		c = codec.NewJSON()
+	}
+	value, headers, err := EncodeMessage(envelope, c)
+	if err != nil {
+		return err
+	}
+	p.broker.append(topic, key, value, headers)
+	return nil
+}
+
+func (p *MemoryPublisher) Close(ctx context.Context) error {
+	return nil
+}
+
+type subscriberOptions struct {
+	retryBackoff time.Duration
+	errorHandler func(message *Message, err error)
+}
+
+type SubscriberOption func(*subscriberOptions)
+
+func defaultSubscriberOptions() subscriberOptions {
+	return subscriberOptions{
+		retryBackoff: time.Second,
+		errorHandler: func(message *Message, err error) {
+			log.Printf("[Memory] %s[%d]@%d: %s\n", message.Topic, message.Partition, message.Offset, err)
+		},
+	}
+}
+
+func WithRetryBackoff(backoff time.Duration) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.retryBackoff = backoff
+	}
+}
+
+func WithErrorHandler(handler func(message *Message, err error)) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.errorHandler = handler
+	}
+}
+
+type MemorySubscriber struct {
+	broker  *MemoryBroker
+	group   string
+	topics  []string
+	options subscriberOptions
+	cursor  int
+}
+
+func (s *MemorySubscriber) Run(ctx context.Context, handler Handler) error {
+	for ctx.Err() == nil {
+		message, changed := s.broker.claim(s.group, s.topics, s.cursor)
+		if message == nil {
+			select {
+			case <-changed:
+			case <-ctx.Done():
+			}
+			continue
+		}
+		s.cursor++
+		err := handler(MessageContext(ctx, message), message)
+		if err == nil {
+			s.broker.release(s.group, message, true)
+			continue
+		}
+		s.options.errorHandler(message, err)
+		if events.IsPermanent(err) {
+			s.broker.release(s.group, message, true)
+			continue
+		}
+		s.broker.release(s.group, message, false)
+		select {
+		case <-time.After(s.options.retryBackoff):
+		case <-ctx.Done():
+		}
+	}
+	return nil
+}
+
+func (s *MemorySubscriber) Close() error {
+	return nil
+}
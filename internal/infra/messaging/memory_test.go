@@ -0,0 +1,139 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func publish(t *testing.T, publisher Publisher, key string, ids ...string) {
+	for _, id := range ids {
+		assert.Nil(t, publisher.PublishSync(context.Background(), events.NewTypedEvent("test", testPayload{id, 10}), []byte(key), "test"))
+	}
+}
+
+func consume(t *testing.T, subscriber Subscriber, stopAfter int, handler func(message *Message, payload testPayload) error) []string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var mu sync.Mutex
+	var ids []string
+	err := subscriber.Run(ctx, func(ctx context.Context, message *Message) error {
+		envelope, err := DecodeMessage(message, newTestRegistry(t))
+		assert.Nil(t, err)
+		payload := testPayload{}
+		assert.Nil(t, envelope.Decode(&payload))
+		mu.Lock()
+		ids = append(ids, payload.Id)
+		if len(ids) == stopAfter {
+			cancel()
+		}
+		mu.Unlock()
+		return handler(message, payload)
+	})
+	assert.Nil(t, err)
+	assert.Len(t, ids, stopAfter)
+	return ids
+}
+
+func TestMemoryBroker(t *testing.T) {
+	broker := NewMemoryBroker(3)
+	publisher := broker.Publisher("walletcore", nil)
+	publish(t, publisher, "a", "1", "2", "3")
+	publish(t, publisher, "b", "4", "5")
+
+	messages := broker.Messages("test")
+	assert.Len(t, messages, 5)
+	partitions := map[string]int32{}
+	sizes := map[int32]int64{}
+	for _, message := range messages {
+		sizes[message.Partition]++
+		if partition, ok := partitions[string(message.Key)]; ok {
+			assert.Equal(t, partition, message.Partition)
+		}
+		partitions[string(message.Key)] = message.Partition
+	}
+
+	byKey := map[string][]string{}
+	consume(t, broker.Subscribe("group", []string{"test"}), 5, func(message *Message, payload testPayload) error {
+		byKey[string(message.Key)] = append(byKey[string(message.Key)], payload.Id)
+		return nil
+	})
+	assert.Equal(t, []string{"1", "2", "3"}, byKey["a"])
+	assert.Equal(t, []string{"4", "5"}, byKey["b"])
+	assert.Equal(t, int64(0), broker.Lag("group", "test"))
+	assert.Equal(t, sizes[partitions["a"]], broker.Committed("group", "test", partitions["a"]))
+
+	assert.Equal(t, int64(5), broker.Lag("other", "test"))
+	consume(t, broker.Subscribe("other", []string{"test"}), 5, func(message *Message, payload testPayload) error { return nil })
+	assert.Equal(t, int64(0), broker.Lag("other", "test"))
+}
+
+func TestMemoryBroker_WithSharedGroup(t *testing.T) {
+	broker := NewMemoryBroker(4)
+	publisher := broker.Publisher("walletcore", nil)
+	for _, key := range []string{"a", "b", "c", "d"} {
+		publish(t, publisher, key, key+"1", key+"2", key+"3")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var mu sync.Mutex
+	processed := map[string]int{}
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			broker.Subscribe("group", []string{"test"}).Run(ctx, func(ctx context.Context, message *Message) error {
+				h, _ := HeadersFrom(ctx)
+				mu.Lock()
+				defer mu.Unlock()
+				processed[h.EventId]++
+				if len(processed) == 12 {
+					cancel()
+				}
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, processed, 12)
+	for _, count := range processed {
+		assert.Equal(t, 1, count)
+	}
+	assert.Equal(t, int64(0), broker.Lag("group", "test"))
+}
+
+func TestMemorySubscriber_WithFailingHandler(t *testing.T) {
+	broker := NewMemoryBroker(1)
+	publish(t, broker.Publisher("walletcore", nil), "a", "1", "2", "3")
+
+	var errs []error
+	subscriber := broker.Subscribe("group", []string{"test"},
+		WithRetryBackoff(10*time.Millisecond),
+		WithErrorHandler(func(message *Message, err error) {
+			errs = append(errs, err)
+		}),
+	)
+	attempts := 0
+	ids := consume(t, subscriber, 4, func(message *Message, payload testPayload) error {
+		switch payload.Id {
+		case "1":
+			attempts++
+			if attempts == 1 {
+				return errors.New("database unavailable")
+			}
+		case "2":
+			return events.Permanent(errors.New("malformed payload"))
+		}
+		return nil
+	})
+	assert.Equal(t, []string{"1", "1", "2", "3"}, ids)
+	assert.Len(t, errs, 2)
+	assert.Equal(t, int64(0), broker.Lag("group", "test"))
+}
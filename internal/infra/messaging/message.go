@@ -0,0 +1,168 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/josimarz/fc-eda-challenge/pkg/events/codec"
+)
+
+const (
+	ContentTypeHeader     = "content-type"
+	StructuredContentType = "application/cloudevents+json"
+	specVersionHeader     = "ce_specversion"
+	idHeader              = "ce_id"
+	typeHeader            = "ce_type"
+	sourceHeader          = "ce_source"
+	timeHeader            = "ce_time"
+	schemaVersionHeader   = "ce_schemaversion"
+	correlationIdHeader   = "ce_correlationid"
+	causationIdHeader     = "ce_causationid"
+	metadataHeader        = "ce_metadata"
+)
+
+func EncodeMessage(envelope *events.Envelope, c codec.Codec) ([]byte, []Header, error) {
+	value, err := c.Encode(envelope)
+	if err != nil {
+		return nil, nil, err
+	}
+	headers := []Header{
+		{Key: ContentTypeHeader, Value: []byte(c.ContentType())},
+		{Key: specVersionHeader, Value: []byte(envelope.SpecVersion)},
+		{Key: idHeader, Value: []byte(envelope.Id)},
+		{Key: typeHeader, Value: []byte(envelope.Type)},
+		{Key: sourceHeader, Value: []byte(envelope.Source)},
+		{Key: timeHeader, Value: []byte(envelope.Time.Format(time.RFC3339Nano))},
+		{Key: schemaVersionHeader, Value: []byte(strconv.Itoa(envelope.SchemaVersion))},
+		{Key: correlationIdHeader, Value: []byte(envelope.CorrelationId)},
+	}
+	if envelope.CausationId != "" {
+		headers = append(headers, Header{Key: causationIdHeader, Value: []byte(envelope.CausationId)})
+	}
+	if len(envelope.Metadata) > 0 {
+		metadata, err := json.Marshal(envelope.Metadata)
+		if err != nil {
+			return nil, nil, err
+		}
+		headers = append(headers, Header{Key: metadataHeader, Value: metadata})
+	}
+	if envelope.TraceParent != "" {
+		headers = append(headers, Header{Key: events.TraceParentHeader, Value: []byte(envelope.TraceParent)})
+	}
+	if envelope.TraceState != "" {
+		headers = append(headers, Header{Key: events.TraceStateHeader, Value: []byte(envelope.TraceState)})
+	}
+	return value, headers, nil
+}
+
+func DecodeMessage(message *Message, codecs *codec.Registry) (*events.Envelope, error) {
+	headers := make(map[string]string)
+	for _, header := range message.Headers {
+		headers[header.Key] = string(header.Value)
+	}
+	contentType, ok := headers[ContentTypeHeader]
+	if !ok || contentType == StructuredContentType {
+		return events.ParseEnvelope(message.Value)
+	}
+	c, err := codecs.Get(contentType)
+	if err != nil {
+		return nil, err
+	}
+	envelope := &events.Envelope{
+		SpecVersion:     headers[specVersionHeader],
+		Id:              headers[idHeader],
+		Type:            headers[typeHeader],
+		Source:          headers[sourceHeader],
+		DataContentType: contentType,
+		CorrelationId:   headers[correlationIdHeader],
+		CausationId:     headers[causationIdHeader],
+		TraceParent:     headers[events.TraceParentHeader],
+		TraceState:      headers[events.TraceStateHeader],
+	}
+	if envelope.SpecVersion != events.SpecVersion || envelope.Id == "" || envelope.Type == "" || envelope.Source == "" {
+		return nil, fmt.Errorf("%w: missing or unsupported ce_ headers", events.ErrInvalidEnvelope)
+	}
+	if envelope.Time, err = time.Parse(time.RFC3339Nano, headers[timeHeader]); err != nil {
+		return nil, fmt.Errorf("%w: %s", events.ErrInvalidEnvelope, err)
+	}
+	if envelope.SchemaVersion, err = strconv.Atoi(headers[schemaVersionHeader]); err != nil {
+		return nil, fmt.Errorf("%w: %s", events.ErrInvalidEnvelope, err)
+	}
+	if metadata, ok := headers[metadataHeader]; ok {
+		if err := json.Unmarshal([]byte(metadata), &envelope.Metadata); err != nil {
+			return nil, fmt.Errorf("%w: %s", events.ErrInvalidEnvelope, err)
+		}
+	}
+	if err := c.Decode(message.Value, envelope); err != nil {
+		return nil, err
+	}
+	return envelope, nil
+}
+
+type headersKey struct{}
+
+type Headers struct {
+	EventId       string
+	EventType     string
+	ContentType   string
+	SchemaVersion int
+	CorrelationId string
+	CausationId   string
+	Metadata      map[string]string
+	Trace         events.TraceContext
+}
+
+func ParseHeaders(message *Message) *Headers {
+	h := &Headers{}
+	for _, header := range message.Headers {
+		value := string(header.Value)
+		switch header.Key {
+		case idHeader:
+			h.EventId = value
+		case typeHeader:
+			h.EventType = value
+		case ContentTypeHeader:
+			h.ContentType = value
+		case schemaVersionHeader:
+			h.SchemaVersion, _ = strconv.Atoi(value)
+		case correlationIdHeader:
+			h.CorrelationId = value
+		case causationIdHeader:
+			h.CausationId = value
+		case metadataHeader:
+			_ = json.Unmarshal(header.Value, &h.Metadata)
+		case events.TraceParentHeader:
+			h.Trace, _ = events.ParseTraceParent(value, h.Trace.State)
+		case events.TraceStateHeader:
+			h.Trace.State = value
+		}
+	}
+	return h
+}
+
+func MessageContext(ctx context.Context, message *Message) context.Context {
+	h := ParseHeaders(message)
+	ctx = context.WithValue(ctx, headersKey{}, h)
+	if h.CorrelationId != "" {
+		ctx = events.WithCorrelationId(ctx, h.CorrelationId)
+	}
+	if h.EventId != "" {
+		ctx = events.WithCausationId(ctx, h.EventId)
+	}
+	for key, value := range h.Metadata {
+		ctx = events.WithMetadata(ctx, key, value)
+	}
+	if h.Trace.IsValid() {
+		ctx = events.WithTraceContext(ctx, h.Trace.Child())
+	}
+	return ctx
+}
+
+func HeadersFrom(ctx context.Context) (*Headers, bool) {
+	h, ok := ctx.Value(headersKey{}).(*Headers)
+	return h, ok
+}
@@ -0,0 +1,120 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/josimarz/fc-eda-challenge/pkg/events/codec"
+	"github.com/stretchr/testify/assert"
+)
+
+type testPayload struct {
+	Id     string  `json:"id"`
+	Amount float64 `json:"amount"`
+}
+
+func newTestRegistry(t *testing.T) *codec.Registry {
+	avro, err := codec.NewAvro(map[string]string{"test.v1": `{
+		"type": "record",
+		"name": "Test",
+		"fields": [
+			{ "name": "id", "type": "string" },
+			{ "name": "amount", "type": "double" }
+		]
+	}`})
+	assert.Nil(t, err)
+	return codec.NewRegistry(codec.NewJSON(), avro)
+}
+
+func TestEncodeMessage(t *testing.T) {
+	registry := newTestRegistry(t)
+	ctx := events.WithMetadata(events.WithCausationId(context.Background(), "cause"), "tenant", "acme")
+	envelope, err := events.NewEnvelope(ctx, "walletcore", events.NewTypedEvent("test", testPayload{"1", 10}))
+	assert.Nil(t, err)
+
+	for _, contentType := range []string{"application/json", "application/avro"} {
+		c, _ := registry.Get(contentType)
+		value, headers, err := EncodeMessage(envelope, c)
+		assert.Nil(t, err)
+		assert.Contains(t, headers, Header{Key: ContentTypeHeader, Value: []byte(contentType)})
+
+		decoded, err := DecodeMessage(&Message{Value: value, Headers: headers}, registry)
+		assert.Nil(t, err)
+		assert.Equal(t, envelope.Id, decoded.Id)
+		assert.Equal(t, "test", decoded.Type)
+		assert.Equal(t, "walletcore", decoded.Source)
+		assert.True(t, envelope.Time.Equal(decoded.Time))
+		assert.Equal(t, envelope.CorrelationId, decoded.CorrelationId)
+		assert.Equal(t, "cause", decoded.CausationId)
+		assert.Equal(t, map[string]string{"tenant": "acme"}, decoded.Metadata)
+		var payload testPayload
+		assert.Nil(t, decoded.Decode(&payload))
+		assert.Equal(t, testPayload{"1", 10}, payload)
+	}
+}
+
+func TestDecodeMessage_WithStructuredEnvelope(t *testing.T) {
+	envelope, _ := events.NewEnvelope(context.Background(), "walletcore", events.NewTypedEvent("test", testPayload{"1", 10}))
+	value, _ := json.Marshal(envelope)
+
+	decoded, err := DecodeMessage(&Message{Value: value}, newTestRegistry(t))
+	assert.Nil(t, err)
+	assert.Equal(t, envelope.Id, decoded.Id)
+}
+
+func TestDecodeMessage_WithUnknownContentType(t *testing.T) {
+	message := &Message{Headers: []Header{{Key: ContentTypeHeader, Value: []byte("application/xml")}}}
+	_, err := DecodeMessage(message, newTestRegistry(t))
+	assert.ErrorIs(t, err, codec.ErrUnknownContentType)
+}
+
+func TestDecodeMessage_WithMissingHeaders(t *testing.T) {
+	message := &Message{Headers: []Header{{Key: ContentTypeHeader, Value: []byte("application/json")}}}
+	_, err := DecodeMessage(message, newTestRegistry(t))
+	assert.ErrorIs(t, err, events.ErrInvalidEnvelope)
+}
+
+func TestMessageContext(t *testing.T) {
+	trace := events.NewTraceContext()
+	trace.State = "vendor=1"
+	ctx := events.WithTraceContext(events.WithCorrelationId(context.Background(), "correlation"), trace)
+	ctx = events.WithMetadata(ctx, "tenant", "acme")
+	envelope, err := events.NewEnvelope(ctx, "walletcore", events.NewVersionedEvent("test", 2, testPayload{"1", 10}))
+	assert.Nil(t, err)
+	value, headers, err := EncodeMessage(envelope, codec.NewJSON())
+	assert.Nil(t, err)
+	assert.Contains(t, headers, Header{Key: events.TraceStateHeader, Value: []byte("vendor=1")})
+
+	ctx = MessageContext(context.Background(), &Message{Value: value, Headers: headers})
+	h, ok := HeadersFrom(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, envelope.Id, h.EventId)
+	assert.Equal(t, "test", h.EventType)
+	assert.Equal(t, "application/json", h.ContentType)
+	assert.Equal(t, 2, h.SchemaVersion)
+	assert.Equal(t, "correlation", events.CorrelationId(ctx))
+	assert.Equal(t, envelope.Id, events.CausationId(ctx))
+	assert.Equal(t, map[string]string{"tenant": "acme"}, events.Metadata(ctx))
+	received, ok := events.TraceContextFrom(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, trace.TraceId, received.TraceId)
+	assert.NotEqual(t, h.Trace.SpanId, received.SpanId)
+	assert.Equal(t, "vendor=1", received.State)
+
+	decoded, err := DecodeMessage(&Message{Value: value, Headers: headers}, codec.NewRegistry(codec.NewJSON()))
+	assert.Nil(t, err)
+	assert.Equal(t, envelope.TraceParent, decoded.TraceParent)
+	assert.Equal(t, "vendor=1", decoded.TraceState)
+}
+
+func TestMessageContext_WithoutHeaders(t *testing.T) {
+	ctx := MessageContext(context.Background(), &Message{Value: []byte(`{}`)})
+	h, ok := HeadersFrom(ctx)
+	assert.True(t, ok)
+	assert.Empty(t, h.EventType)
+	assert.Empty(t, events.CorrelationId(ctx))
+	_, ok = events.TraceContextFrom(ctx)
+	assert.False(t, ok)
+}
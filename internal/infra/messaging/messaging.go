@@ -0,0 +1,36 @@
+package messaging
+
+import (
+	"context"
+	"time"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+)
+
+type Header struct {
+	Key   string
+	Value []byte
+}
+
+type Message struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Headers   []Header
+	Timestamp time.Time
+}
+
+type Handler func(ctx context.Context, message *Message) error
+
+type Publisher interface {
+	Publish(ctx context.Context, event events.Event, key []byte, topic string) error
+	PublishSync(ctx context.Context, event events.Event, key []byte, topic string) error
+	Close(ctx context.Context) error
+}
+
+type Subscriber interface {
+	Run(ctx context.Context, handler Handler) error
+	Close() error
+}
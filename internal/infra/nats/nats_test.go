@@ -0,0 +1,200 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/josimarz/fc-eda-challenge/internal/infra/messaging"
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/josimarz/fc-eda-challenge/pkg/events/codec"
+	"github.com/nats-io/nats-server/v2/server"
+	cnats "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type testPayload struct {
+	Id     string  `json:"id"`
+	Amount float64 `json:"amount"`
+}
+
+type NatsTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	cancel context.CancelFunc
+	server *server.Server
+	conn   *cnats.Conn
+	js     jetstream.JetStream
+	mu     sync.Mutex
+	errs   []error
+}
+
+func (suite *NatsTestSuite) SetupTest() {
+	suite.ctx, suite.cancel = context.WithTimeout(context.Background(), 30*time.Second)
+	s, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, JetStream: true, StoreDir: suite.T().TempDir(), NoLog: true, NoSigs: true})
+	suite.Require().Nil(err)
+	go s.Start()
+	suite.Require().True(s.ReadyForConnections(5 * time.Second))
+	suite.server = s
+	suite.conn, err = cnats.Connect(s.ClientURL())
+	suite.Require().Nil(err)
+	suite.js, err = jetstream.New(suite.conn)
+	suite.Require().Nil(err)
+	suite.Require().Nil(EnsureStreams(suite.ctx, suite.js, []string{"test"}))
+	suite.errs = nil
+}
+
+func (suite *NatsTestSuite) TearDownTest() {
+	suite.conn.Close()
+	suite.server.Shutdown()
+	suite.cancel()
+}
+
+func (suite *NatsTestSuite) newSubscriber(opts ...SubscriberOption) *Subscriber {
+	opts = append([]SubscriberOption{
+		WithRetryBackoff(10 * time.Millisecond),
+		WithErrorHandler(func(message *messaging.Message, err error) {
+			suite.mu.Lock()
+			defer suite.mu.Unlock()
+			suite.errs = append(suite.errs, err)
+		}),
+	}, opts...)
+	subscriber, err := NewSubscriber(suite.ctx, suite.js, "group", []string{"test"}, opts...)
+	suite.Require().Nil(err)
+	return subscriber
+}
+
+func (suite *NatsTestSuite) publish(ids ...string) {
+	publisher := NewPublisher(suite.js, "test", nil)
+	for _, id := range ids {
+		suite.Require().Nil(publisher.PublishSync(suite.ctx, events.NewTypedEvent("test", testPayload{id, 10}), []byte("1"), "test"))
+	}
+}
+
+func (suite *NatsTestSuite) run(subscriber *Subscriber, stopAfter int, handler func(message *messaging.Message, payload testPayload) error) []string {
+	ctx, cancel := context.WithCancel(suite.ctx)
+	defer cancel()
+	var ids []string
+	err := subscriber.Run(ctx, func(ctx context.Context, message *messaging.Message) error {
+		envelope, err := messaging.DecodeMessage(message, codec.NewRegistry(codec.NewJSON()))
+		suite.Require().Nil(err)
+		payload := testPayload{}
+		suite.Require().Nil(envelope.Decode(&payload))
+		ids = append(ids, payload.Id)
+		if len(ids) == stopAfter {
+			cancel()
+		}
+		return handler(message, payload)
+	})
+	suite.Nil(err)
+	return ids
+}
+
+func (suite *NatsTestSuite) deadLetters() []jetstream.Msg {
+	consumer, err := suite.js.OrderedConsumer(suite.ctx, StreamName("test"), jetstream.OrderedConsumerConfig{FilterSubjects: []string{DeadLetterSubject("test")}})
+	suite.Require().Nil(err)
+	batch, err := consumer.FetchNoWait(10)
+	suite.Require().Nil(err)
+	var messages []jetstream.Msg
+	for msg := range batch.Messages() {
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+func (suite *NatsTestSuite) TestRun() {
+	suite.publish("1", "2")
+	subscriber := suite.newSubscriber()
+	var keys []string
+	ids := suite.run(subscriber, 2, func(message *messaging.Message, payload testPayload) error {
+		keys = append(keys, string(message.Key))
+		return nil
+	})
+	suite.Equal([]string{"1", "2"}, ids)
+	suite.Equal([]string{"1", "1"}, keys)
+
+	suite.publish("3")
+	subscriber = suite.newSubscriber()
+	ids = suite.run(subscriber, 1, func(message *messaging.Message, payload testPayload) error { return nil })
+	suite.Equal([]string{"3"}, ids)
+}
+
+func (suite *NatsTestSuite) TestRun_WithFailingHandler() {
+	suite.publish("1", "2", "3")
+	subscriber := suite.newSubscriber()
+	attempts := 0
+	ids := suite.run(subscriber, 4, func(message *messaging.Message, payload testPayload) error {
+		switch payload.Id {
+		case "1":
+			attempts++
+			if attempts == 1 {
+				return errors.New("database unavailable")
+			}
+		case "2":
+			return events.Permanent(errors.New("malformed payload"))
+		}
+		return nil
+	})
+	suite.Equal([]string{"1", "1", "2", "3"}, ids)
+	suite.Len(suite.errs, 2)
+
+	consumer, err := suite.js.Consumer(suite.ctx, StreamName("test"), "group")
+	suite.Require().Nil(err)
+	info, err := consumer.Info(suite.ctx)
+	suite.Require().Nil(err)
+	suite.Equal(uint64(3), info.AckFloor.Stream)
+	suite.Equal(uint64(0), info.NumPending)
+
+	dead := suite.deadLetters()
+	suite.Require().Len(dead, 1)
+	suite.Equal("malformed payload", dead[0].Headers().Get("dlq_error"))
+	suite.Equal("test", dead[0].Headers().Get("dlq_topic"))
+	suite.Equal("2", dead[0].Headers().Get("dlq_offset"))
+}
+
+func (suite *NatsTestSuite) TestRun_WithMaxDeliver() {
+	suite.publish("1", "2")
+	subscriber := suite.newSubscriber(WithMaxDeliver(2))
+	ids := suite.run(subscriber, 3, func(message *messaging.Message, payload testPayload) error {
+		if payload.Id == "1" {
+			return errors.New("database unavailable")
+		}
+		return nil
+	})
+	suite.Equal([]string{"1", "1", "2"}, ids)
+
+	dead := suite.deadLetters()
+	suite.Require().Len(dead, 1)
+	suite.Equal("database unavailable", dead[0].Headers().Get("dlq_error"))
+	suite.Equal("2", dead[0].Headers().Get("dlq_attempts"))
+}
+
+func (suite *NatsTestSuite) TestPublishSync_WithDuplicateEvent() {
+	publisher := NewPublisher(suite.js, "test", nil)
+	event := events.NewTypedEvent("test", testPayload{"1", 10})
+	suite.Nil(publisher.PublishSync(suite.ctx, event, nil, "test"))
+	suite.Nil(publisher.PublishSync(suite.ctx, event, nil, "test"))
+	suite.Nil(publisher.Publish(suite.ctx, events.NewTypedEvent("test", testPayload{"2", 10}), nil, "test"))
+	suite.Nil(publisher.Close(suite.ctx))
+	suite.Equal(int64(3), publisher.Delivered())
+	suite.Equal(int64(0), publisher.Failed())
+
+	stream, err := suite.js.Stream(suite.ctx, StreamName("test"))
+	suite.Require().Nil(err)
+	info, err := stream.Info(suite.ctx)
+	suite.Require().Nil(err)
+	suite.Equal(uint64(2), info.State.Msgs)
+}
+
+func TestStreamName(t *testing.T) {
+	assert.Equal(t, "PAYMENT_REQUESTS", StreamName("payment_requests"))
+	assert.Equal(t, "TRANSACTIONS_RETRY_5S", StreamName("transactions.retry.5s"))
+}
+
+func TestNatsTestSuite(t *testing.T) {
+	suite.Run(t, new(NatsTestSuite))
+}
@@ -0,0 +1,118 @@
+package nats
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+
+	"github.com/josimarz/fc-eda-challenge/internal/infra/messaging"
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/josimarz/fc-eda-challenge/pkg/events/codec"
+	cnats "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const keyHeader = "key"
+
+var _ messaging.Publisher = (*Publisher)(nil)
+
+type Publisher struct {
+	Source    string
+	Codecs    map[string]codec.Codec
+	js        jetstream.JetStream
+	pending   sync.WaitGroup
+	delivered atomic.Int64
+	failed    atomic.Int64
+}
+
+func NewPublisher(js jetstream.JetStream, source string, codecs map[string]codec.Codec) *Publisher {
+	return &Publisher{Source: source, Codecs: codecs, js: js}
+}
+
+func (p *Publisher) Publish(ctx context.Context, event events.Event, key []byte, topic string) error {
+	message, id, err := p.message(ctx, event, key, topic)
+	if err != nil {
+		return err
+	}
+	future, err := p.js.PublishMsgAsync(message, jetstream.WithMsgID(id))
+	if err != nil {
+		return err
+	}
+	p.pending.Add(1)
+	go p.acknowledgement(future)
+	return nil
+}
+
+func (p *Publisher) PublishSync(ctx context.Context, event events.Event, key []byte, topic string) error {
+	message, id, err := p.message(ctx, event, key, topic)
+	if err != nil {
+		return err
+	}
+	if _, err := p.js.PublishMsg(ctx, message, jetstream.WithMsgID(id)); err != nil {
+		p.failed.Add(1)
+		return err
+	}
+	p.delivered.Add(1)
+	return nil
+}
+
+func (p *Publisher) Delivered() int64 {
+	return p.delivered.Load()
+}
+
+func (p *Publisher) Failed() int64 {
+	return p.failed.Load()
+}
+
+func (p *Publisher) Close(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		p.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *Publisher) acknowledgement(future jetstream.PubAckFuture) {
+	defer p.pending.Done()
+	select {
+	case <-future.Ok():
+		p.delivered.Add(1)
+	case err := <-future.Err():
+		p.failed.Add(1)
+		log.Printf("[NATS] delivery to %s failed: %s\n", future.Msg().Subject, err)
+	}
+}
+
+func (p *Publisher) message(ctx context.Context, event events.Event, key []byte, topic string) (*cnats.Msg, string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+	envelope, err := events.NewEnvelope(ctx, p.Source, event)
+	if err != nil {
+		return nil, "", err
+	}
+	c, ok := p.Codecs[topic]
+	if !ok {
+		c = codec.NewJSON()
+	}
+	value, headers, err := messaging.EncodeMessage(envelope, c)
+	if err != nil {
+		return nil, "", err
+	}
+	message := cnats.NewMsg(topic)
+	message.Data = value
+	for _, header := range headers {
+		message.Header.Set(header.Key, string(header.Value))
+	}
+	if key != nil {
+		message.Header.Set(keyHeader, string(key))
+	}
+	return message, envelope.Id, nil
+}
@@ -0,0 +1,37 @@
+package nats
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	duplicateWindow  = 2 * time.Minute
+	DeadLetterSuffix = ".dlq"
+)
+
+func DeadLetterSubject(topic string) string {
+	return topic + DeadLetterSuffix
+}
+
+func StreamName(topic string) string {
+	return strings.ToUpper(strings.NewReplacer(".", "_", "*", "_", ">", "_").Replace(topic))
+}
+
+func EnsureStreams(ctx context.Context, js jetstream.JetStream, topics []string) error {
+	for _, topic := range topics {
+		_, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+			Name:       StreamName(topic),
+			Subjects:   []string{topic, DeadLetterSubject(topic)},
+			Storage:    jetstream.FileStorage,
+			Duplicates: duplicateWindow,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
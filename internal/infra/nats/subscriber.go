@@ -0,0 +1,238 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/josimarz/fc-eda-challenge/internal/infra/messaging"
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	cnats "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+var _ messaging.Subscriber = (*Subscriber)(nil)
+
+const (
+	dlqErrorHeader    = "dlq_error"
+	dlqAttemptsHeader = "dlq_attempts"
+	dlqTopicHeader    = "dlq_topic"
+	dlqOffsetHeader   = "dlq_offset"
+	dlqFailedAtHeader = "dlq_failed_at"
+)
+
+type subscriberOptions struct {
+	ackWait      time.Duration
+	maxDeliver   int
+	retryBackoff time.Duration
+	errorHandler func(message *messaging.Message, err error)
+}
+
+type SubscriberOption func(*subscriberOptions)
+
+func defaultSubscriberOptions() subscriberOptions {
+	return subscriberOptions{
+		ackWait:      30 * time.Second,
+		maxDeliver:   5,
+		retryBackoff: time.Second,
+		errorHandler: func(message *messaging.Message, err error) {
+			if message != nil {
+				log.Printf("[NATS] %s@%d: %s\n", message.Topic, message.Offset, err)
+				return
+			}
+			log.Printf("[NATS] %s\n", err)
+		},
+	}
+}
+
+func WithAckWait(ackWait time.Duration) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.ackWait = ackWait
+	}
+}
+
+func WithMaxDeliver(maxDeliver int) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.maxDeliver = maxDeliver
+	}
+}
+
+func WithRetryBackoff(backoff time.Duration) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.retryBackoff = backoff
+	}
+}
+
+func WithErrorHandler(handler func(message *messaging.Message, err error)) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.errorHandler = handler
+	}
+}
+
+type Subscriber struct {
+	Group     string
+	Topics    []string
+	js        jetstream.JetStream
+	consumers []jetstream.Consumer
+	options   subscriberOptions
+	mu        sync.Mutex
+	iterators []jetstream.MessagesContext
+}
+
+func NewSubscriber(ctx context.Context, js jetstream.JetStream, group string, topics []string, opts ...SubscriberOption) (*Subscriber, error) {
+	s := &Subscriber{
+		Group:   group,
+		Topics:  topics,
+		js:      js,
+		options: defaultSubscriberOptions(),
+	}
+	for _, opt := range opts {
+		opt(&s.options)
+	}
+	for _, topic := range topics {
+		consumer, err := js.CreateOrUpdateConsumer(ctx, StreamName(topic), jetstream.ConsumerConfig{
+			Durable:       group,
+			FilterSubject: topic,
+			DeliverPolicy: jetstream.DeliverAllPolicy,
+			AckPolicy:     jetstream.AckExplicitPolicy,
+			AckWait:       s.options.ackWait,
+			MaxDeliver:    s.options.maxDeliver,
+			MaxAckPending: 1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		s.consumers = append(s.consumers, consumer)
+	}
+	return s, nil
+}
+
+func (s *Subscriber) Run(ctx context.Context, handler messaging.Handler) error {
+	received := make(chan jetstream.Msg)
+	var wg sync.WaitGroup
+	for _, consumer := range s.consumers {
+		iterator, err := consumer.Messages()
+		if err != nil {
+			s.Close()
+			return err
+		}
+		s.mu.Lock()
+		s.iterators = append(s.iterators, iterator)
+		s.mu.Unlock()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				msg, err := iterator.Next()
+				if errors.Is(err, jetstream.ErrMsgIteratorClosed) {
+					return
+				}
+				if err != nil {
+					s.options.errorHandler(nil, err)
+					continue
+				}
+				select {
+				case received <- msg:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	defer wg.Wait()
+	defer s.Close()
+	for {
+		select {
+		case msg := <-received:
+			s.handle(ctx, msg, handler)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (s *Subscriber) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, iterator := range s.iterators {
+		iterator.Stop()
+	}
+	s.iterators = nil
+	return nil
+}
+
+func (s *Subscriber) handle(ctx context.Context, msg jetstream.Msg, handler messaging.Handler) {
+	message := fromNats(msg)
+	err := handler(messaging.MessageContext(ctx, message), message)
+	if err == nil {
+		if err := msg.Ack(); err != nil {
+			s.options.errorHandler(message, err)
+		}
+		return
+	}
+	s.options.errorHandler(message, err)
+	metadata, _ := msg.Metadata()
+	exhausted := metadata != nil && s.options.maxDeliver > 0 && int(metadata.NumDelivered) >= s.options.maxDeliver
+	if !events.IsPermanent(err) && !exhausted {
+		err = msg.NakWithDelay(s.options.retryBackoff)
+	} else if dlqErr := s.deadLetter(ctx, msg, err, metadata); dlqErr != nil {
+		s.options.errorHandler(message, dlqErr)
+		err = msg.NakWithDelay(s.options.retryBackoff)
+	} else {
+		err = msg.Term()
+	}
+	if err != nil {
+		s.options.errorHandler(message, err)
+	}
+}
+
+// deadLetter copies a message that failed permanently, or ran out of
+// deliveries, to the ".dlq" subject of its topic, with the failure recorded in
+// the same headers the Kafka dead letter queue uses.
+func (s *Subscriber) deadLetter(ctx context.Context, msg jetstream.Msg, cause error, metadata *jetstream.MsgMetadata) error {
+	dead := cnats.NewMsg(DeadLetterSubject(msg.Subject()))
+	dead.Data = msg.Data()
+	for key, values := range msg.Headers() {
+		if key == cnats.MsgIdHdr {
+			continue
+		}
+		for _, value := range values {
+			dead.Header.Add(key, value)
+		}
+	}
+	attempts, offset := 1, uint64(0)
+	if metadata != nil {
+		attempts, offset = int(metadata.NumDelivered), metadata.Sequence.Stream
+	}
+	dead.Header.Set(dlqErrorHeader, cause.Error())
+	dead.Header.Set(dlqAttemptsHeader, strconv.Itoa(attempts))
+	dead.Header.Set(dlqTopicHeader, msg.Subject())
+	dead.Header.Set(dlqOffsetHeader, strconv.FormatUint(offset, 10))
+	dead.Header.Set(dlqFailedAtHeader, time.Now().UTC().Format(time.RFC3339Nano))
+	_, err := s.js.PublishMsg(ctx, dead)
+	return err
+}
+
+func fromNats(msg jetstream.Msg) *messaging.Message {
+	message := &messaging.Message{
+		Topic: msg.Subject(),
+		Value: msg.Data(),
+	}
+	if metadata, err := msg.Metadata(); err == nil {
+		message.Offset = int64(metadata.Sequence.Stream)
+		message.Timestamp = metadata.Timestamp
+	}
+	for key, values := range msg.Headers() {
+		if key == keyHeader {
+			message.Key = []byte(values[0])
+			continue
+		}
+		for _, value := range values {
+			message.Headers = append(message.Headers, messaging.Header{Key: key, Value: []byte(value)})
+		}
+	}
+	return message
+}
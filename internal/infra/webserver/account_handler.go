@@ -29,7 +29,7 @@ func (h *CreateAccountHandler) GetHandlerFunc() http.HandlerFunc {
 		input := usecase.CreateAccountInput{
 			CustomerId: chi.URLParam(r, "id"),
 		}
-		output, err := h.uc.Execute(input)
+		output, err := h.uc.Execute(r.Context(), input)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -62,7 +62,7 @@ func (h *ListCustomerAccountsHandler) GetHandlerFunc() http.HandlerFunc {
 		input := usecase.ListCustomerAccountsInput{
 			CustomerId: chi.URLParam(r, "id"),
 		}
-		output, err := h.uc.Execute(input)
+		output, err := h.uc.Execute(r.Context(), input)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -98,7 +98,7 @@ func (h *DepositHandler) GetHandlerFunc() http.HandlerFunc {
 			return
 		}
 		input.Id = chi.URLParam(r, "id")
-		output, err := h.uc.Execute(&input)
+		output, err := h.uc.Execute(r.Context(), &input)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -134,7 +134,7 @@ func (h *WithdrawHandler) GetHandlerFunc() http.HandlerFunc {
 			return
 		}
 		input.Id = chi.URLParam(r, "id")
-		output, err := h.uc.Execute(&input)
+		output, err := h.uc.Execute(r.Context(), &input)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -166,7 +166,7 @@ func (h *ShowAccountBalanceHandler) GetHandlerFunc() http.HandlerFunc {
 		input := &usecase.ShowAccountBalanceInput{
 			Id: chi.URLParam(r, "id"),
 		}
-		output, err := h.uc.Execute(input)
+		output, err := h.uc.Execute(r.Context(), input)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -31,7 +31,7 @@ func (h *CreateCustomerHandler) GetHandlerFunc() http.HandlerFunc {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		output, err := h.uc.Execute(&input)
+		output, err := h.uc.Execute(r.Context(), &input)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -64,7 +64,7 @@ func (h *FindCustomerHandler) GetHandlerFunc() http.HandlerFunc {
 		input := usecase.FindCustomerInput{
 			Id: chi.URLParam(r, "id"),
 		}
-		output, err := h.uc.Execute(&input)
+		output, err := h.uc.Execute(r.Context(), &input)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -94,7 +94,7 @@ func (h *ListCustomersHandler) GetPattern() string {
 
 func (h *ListCustomersHandler) GetHandlerFunc() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		output, err := h.uc.Execute(&usecase.ListCustomersInput{})
+		output, err := h.uc.Execute(r.Context(), &usecase.ListCustomersInput{})
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -130,7 +130,7 @@ func (h *UpdateCustomerHandler) GetHandlerFunc() http.HandlerFunc {
 			return
 		}
 		input.Id = chi.URLParam(r, "id")
-		output, err := h.uc.Execute(&input)
+		output, err := h.uc.Execute(r.Context(), &input)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -164,7 +164,7 @@ func (h *DeleteCustomerHandler) GetHandlerFunc() http.HandlerFunc {
 		input := usecase.DeleteCustomerInput{
 			Id: chi.URLParam(r, "id"),
 		}
-		output, err := h.uc.Execute(&input)
+		output, err := h.uc.Execute(r.Context(), &input)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -0,0 +1,112 @@
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/josimarz/fc-eda-challenge/internal/usecase"
+)
+
+type RegisterKeyHandler struct {
+	uc *usecase.RegisterKeyUseCase
+}
+
+func NewRegisterKeyHandler(uc *usecase.RegisterKeyUseCase) *RegisterKeyHandler {
+	return &RegisterKeyHandler{uc}
+}
+
+func (h *RegisterKeyHandler) GetMethod() string {
+	return "POST"
+}
+
+func (h *RegisterKeyHandler) GetPattern() string {
+	return "/customers/{id}/keys"
+}
+
+func (h *RegisterKeyHandler) GetHandlerFunc() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input usecase.RegisterKeyInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		input.CustomerId = chi.URLParam(r, "id")
+		output, err := h.uc.Execute(r.Context(), &input)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+type ListCustomerKeysHandler struct {
+	uc *usecase.ListCustomerKeysUseCase
+}
+
+func NewListCustomerKeysHandler(uc *usecase.ListCustomerKeysUseCase) *ListCustomerKeysHandler {
+	return &ListCustomerKeysHandler{uc}
+}
+
+func (h *ListCustomerKeysHandler) GetMethod() string {
+	return "GET"
+}
+
+func (h *ListCustomerKeysHandler) GetPattern() string {
+	return "/customers/{id}/keys"
+}
+
+func (h *ListCustomerKeysHandler) GetHandlerFunc() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		input := &usecase.ListCustomerKeysInput{
+			CustomerId: chi.URLParam(r, "id"),
+		}
+		output, err := h.uc.Execute(r.Context(), input)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+type DeleteKeyHandler struct {
+	uc *usecase.DeleteKeyUseCase
+}
+
+func NewDeleteKeyHandler(uc *usecase.DeleteKeyUseCase) *DeleteKeyHandler {
+	return &DeleteKeyHandler{uc}
+}
+
+func (h *DeleteKeyHandler) GetMethod() string {
+	return "DELETE"
+}
+
+func (h *DeleteKeyHandler) GetPattern() string {
+	return "/customers/{id}/keys/{key}"
+}
+
+func (h *DeleteKeyHandler) GetHandlerFunc() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		input := &usecase.DeleteKeyInput{
+			CustomerId: chi.URLParam(r, "id"),
+			Value:      chi.URLParam(r, "key"),
+		}
+		output, err := h.uc.Execute(r.Context(), input)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package webserver
+
+import (
+	"expvar"
+	"net/http"
+)
+
+type MetricsHandler struct{}
+
+func NewMetricsHandler() *MetricsHandler {
+	return &MetricsHandler{}
+}
+
+func (h *MetricsHandler) GetMethod() string {
+	return "GET"
+}
+
+func (h *MetricsHandler) GetPattern() string {
+	return "/debug/vars"
+}
+
+func (h *MetricsHandler) GetHandlerFunc() http.HandlerFunc {
+	return expvar.Handler().ServeHTTP
+}
@@ -0,0 +1,157 @@
+package webserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/josimarz/fc-eda-challenge/internal/gateway"
+	"github.com/josimarz/fc-eda-challenge/internal/usecase"
+)
+
+type CreatePaymentRequestHandler struct {
+	uc *usecase.CreatePaymentRequestUseCase
+}
+
+func NewCreatePaymentRequestHandler(uc *usecase.CreatePaymentRequestUseCase) *CreatePaymentRequestHandler {
+	return &CreatePaymentRequestHandler{uc}
+}
+
+func (h *CreatePaymentRequestHandler) GetMethod() string {
+	return "POST"
+}
+
+func (h *CreatePaymentRequestHandler) GetPattern() string {
+	return "/customers/{id}/payment-requests"
+}
+
+func (h *CreatePaymentRequestHandler) GetHandlerFunc() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input usecase.CreatePaymentRequestInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		input.CustomerId = chi.URLParam(r, "id")
+		output, err := h.uc.Execute(r.Context(), &input)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+type ListPaymentRequestsHandler struct {
+	uc *usecase.ListPaymentRequestsUseCase
+}
+
+func NewListPaymentRequestsHandler(uc *usecase.ListPaymentRequestsUseCase) *ListPaymentRequestsHandler {
+	return &ListPaymentRequestsHandler{uc}
+}
+
+func (h *ListPaymentRequestsHandler) GetMethod() string {
+	return "GET"
+}
+
+func (h *ListPaymentRequestsHandler) GetPattern() string {
+	return "/customers/{id}/payment-requests"
+}
+
+func (h *ListPaymentRequestsHandler) GetHandlerFunc() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		input := &usecase.ListPaymentRequestsInput{
+			CustomerId: chi.URLParam(r, "id"),
+			Status:     r.URL.Query().Get("status"),
+		}
+		output, err := h.uc.Execute(r.Context(), input)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+type AcceptPaymentRequestHandler struct {
+	uc *usecase.AcceptPaymentRequestUseCase
+}
+
+func NewAcceptPaymentRequestHandler(uc *usecase.AcceptPaymentRequestUseCase) *AcceptPaymentRequestHandler {
+	return &AcceptPaymentRequestHandler{uc}
+}
+
+func (h *AcceptPaymentRequestHandler) GetMethod() string {
+	return "POST"
+}
+
+func (h *AcceptPaymentRequestHandler) GetPattern() string {
+	return "/customers/{id}/payment-requests/{requestId}/accept"
+}
+
+func (h *AcceptPaymentRequestHandler) GetHandlerFunc() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		input := &usecase.AnswerPaymentRequestInput{
+			CustomerId: chi.URLParam(r, "id"),
+			Id:         chi.URLParam(r, "requestId"),
+		}
+		output, err := h.uc.Execute(r.Context(), input)
+		if errors.Is(err, gateway.ErrConflict) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+type DeclinePaymentRequestHandler struct {
+	uc *usecase.DeclinePaymentRequestUseCase
+}
+
+func NewDeclinePaymentRequestHandler(uc *usecase.DeclinePaymentRequestUseCase) *DeclinePaymentRequestHandler {
+	return &DeclinePaymentRequestHandler{uc}
+}
+
+func (h *DeclinePaymentRequestHandler) GetMethod() string {
+	return "POST"
+}
+
+func (h *DeclinePaymentRequestHandler) GetPattern() string {
+	return "/customers/{id}/payment-requests/{requestId}/decline"
+}
+
+func (h *DeclinePaymentRequestHandler) GetHandlerFunc() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		input := &usecase.AnswerPaymentRequestInput{
+			CustomerId: chi.URLParam(r, "id"),
+			Id:         chi.URLParam(r, "requestId"),
+		}
+		output, err := h.uc.Execute(r.Context(), input)
+		if errors.Is(err, gateway.ErrConflict) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
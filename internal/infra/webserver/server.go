@@ -1,10 +1,12 @@
 package webserver
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/chi/v5"
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
 )
 
 type Handler interface {
@@ -17,6 +19,7 @@ type Server struct {
 	Port     string
 	Router   chi.Router
 	Handlers []Handler
+	server   *http.Server
 }
 
 func NewServer(port string) *Server {
@@ -33,6 +36,7 @@ func (s *Server) AddHandler(handler Handler) {
 
 func (s *Server) Start() error {
 	s.Router.Use(middleware.Logger)
+	s.Router.Use(correlation)
 	for _, handler := range s.Handlers {
 		s.Router.MethodFunc(
 			handler.GetMethod(),
@@ -40,5 +44,25 @@ func (s *Server) Start() error {
 			handler.GetHandlerFunc(),
 		)
 	}
-	return http.ListenAndServe(s.Port, s.Router)
+	s.server = &http.Server{Addr: s.Port, Handler: s.Router}
+	return s.server.ListenAndServe()
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.server == nil {
+		return nil
+	}
+	return s.server.Shutdown(ctx)
+}
+
+func correlation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := r.Header.Get("X-Correlation-Id"); id != "" {
+			r = r.WithContext(events.WithCorrelationId(r.Context(), id))
+		}
+		if trace, err := events.ParseTraceParent(r.Header.Get(events.TraceParentHeader), r.Header.Get(events.TraceStateHeader)); err == nil {
+			r = r.WithContext(events.WithTraceContext(r.Context(), trace))
+		}
+		next.ServeHTTP(w, r)
+	})
 }
@@ -2,6 +2,7 @@ package webserver
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	eventhandling "github.com/josimarz/fc-eda-challenge/internal/event_handling"
@@ -10,11 +11,12 @@ import (
 )
 
 type CreateTransactionHandler struct {
-	ed *events.EventDispatcher
+	ed events.Dispatcher
+	uc *usecase.ResolveKeyUseCase
 }
 
-func NewCreateTransactionHandler(ed *events.EventDispatcher) *CreateTransactionHandler {
-	return &CreateTransactionHandler{ed}
+func NewCreateTransactionHandler(ed events.Dispatcher, uc *usecase.ResolveKeyUseCase) *CreateTransactionHandler {
+	return &CreateTransactionHandler{ed, uc}
 }
 
 func (h *CreateTransactionHandler) GetMethod() string {
@@ -32,9 +34,32 @@ func (h *CreateTransactionHandler) GetHandlerFunc() http.HandlerFunc {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		event := eventhandling.NewTransactionCreatedEvent()
-		event.SetPayload(input)
-		h.ed.Dispatch(event)
+		if input.ToKey != "" {
+			if input.To != "" {
+				http.Error(w, "either to or toKey must be informed, not both", http.StatusBadRequest)
+				return
+			}
+			output, err := h.uc.Execute(r.Context(), &usecase.ResolveKeyInput{Value: input.ToKey})
+			if errors.Is(err, usecase.ErrKeyNotFound) || errors.Is(err, usecase.ErrInvalidKey) {
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+				return
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			input.To = output.AccountId
+			input.ToKey = ""
+		}
+		event := eventhandling.NewTransactionCreatedEvent(eventhandling.TransactionCreatedPayload{
+			From:   input.From,
+			To:     input.To,
+			Amount: input.Amount,
+		})
+		if err := h.ed.Dispatch(r.Context(), event); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
@@ -0,0 +1,49 @@
+// Package service wires the walletcore and transactions services on top of a
+// messaging transport chosen by the caller, so cmd/walletcore,
+// cmd/transactions and cmd/local only differ in how messages travel.
+package service
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/josimarz/fc-eda-challenge/configs"
+	"github.com/josimarz/fc-eda-challenge/internal/gateway"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/database/mysql"
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+)
+
+var (
+	recording = []events.Middleware{
+		events.Retry(3, events.ExponentialBackoff(100*time.Millisecond, 2*time.Second)),
+		events.Timeout(10 * time.Second),
+	}
+	publishing = []events.Middleware{
+		events.Timeout(10 * time.Second),
+	}
+)
+
+func newEventDispatcher(name string) *events.EventDispatcher {
+	return events.NewEventDispatcher(events.WithMiddleware(
+		events.Logging(log.Default()),
+		events.Metrics(events.NewExpvarMetrics(name+"_events")),
+		events.Recover(),
+	))
+}
+
+func newAccountGateway(config *configs.Config, db *sql.DB, source string) gateway.AccountGateway {
+	if config.AccountStore == "eventsourced" {
+		return mysql.NewEventSourcedAccountGateway(db, mysql.NewEventStore(db), source, config.AccountSnapshotInterval)
+	}
+	return mysql.NewAccountGateway(db)
+}
+
+// classify marks every error that retrying cannot fix as permanent, so the
+// transport dead-letters the message instead of redelivering it.
+func classify(err error) error {
+	if mysql.IsTransient(err) {
+		return err
+	}
+	return events.Permanent(err)
+}
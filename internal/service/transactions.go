@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/josimarz/fc-eda-challenge/configs"
+	eventhandling "github.com/josimarz/fc-eda-challenge/internal/event_handling"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/database/mysql"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/messaging"
+	"github.com/josimarz/fc-eda-challenge/internal/usecase"
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/josimarz/fc-eda-challenge/pkg/events/codec"
+	"github.com/josimarz/fc-eda-challenge/pkg/eventstore"
+)
+
+// Transactions is the transactions service: it consumes the transactions
+// topic, records each transfer and emits balances.updated.
+type Transactions struct {
+	codecs                   *codec.Registry
+	schemas                  *events.SchemaRegistry
+	eventDispatcher          *events.EventDispatcher
+	createTransactionUseCase *usecase.CreateTransactionUseCase
+}
+
+func NewTransactions(config *configs.Config, walletCoreDB, transactionsDB *sql.DB, codecs *codec.Registry, publisher messaging.Publisher) (*Transactions, error) {
+	s := &Transactions{
+		codecs:          codecs,
+		schemas:         eventhandling.NewSchemaRegistry(),
+		eventDispatcher: newEventDispatcher("transactions"),
+	}
+	err := errors.Join(
+		s.eventDispatcher.Register("#", eventhandling.NewAuditHandler("transactions")),
+		s.eventDispatcher.Register("#", eventstore.NewRecorder(mysql.NewEventStore(transactionsDB), "transactions", eventstore.ByAggregate(eventhandling.PartitionKey)), recording...),
+		events.Subscribe[eventhandling.BalancesUpdatedPayload](s.eventDispatcher, eventhandling.BalancesUpdated, eventhandling.NewBalancesUpdatedHandler(publisher), publishing...),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	accountGateway := newAccountGateway(config, walletCoreDB, "transactions")
+	transactionGateway := mysql.NewTransactionGateway(transactionsDB)
+	s.createTransactionUseCase = usecase.NewCreateTransactionUseCase(transactionGateway, accountGateway, s.eventDispatcher)
+	return s, nil
+}
+
+// HandleTransactionMessage records the transfer requested by a transactions
+// message.
+func (s *Transactions) HandleTransactionMessage(ctx context.Context, message *messaging.Message) error {
+	envelope, err := messaging.DecodeMessage(message, s.codecs)
+	if err != nil {
+		return events.Permanent(err)
+	}
+	if err := s.schemas.Upcast(envelope); err != nil {
+		return events.Permanent(err)
+	}
+	input := usecase.CreateTransactionInput{Id: envelope.Id}
+	if err := envelope.Decode(&input); err != nil {
+		return events.Permanent(err)
+	}
+	if _, err := s.createTransactionUseCase.Execute(envelope.Context(ctx), &input); err != nil {
+		return classify(err)
+	}
+	return nil
+}
+
+func (s *Transactions) Shutdown(ctx context.Context) error {
+	return s.eventDispatcher.Shutdown(ctx)
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/josimarz/fc-eda-challenge/configs"
+	eventhandling "github.com/josimarz/fc-eda-challenge/internal/event_handling"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/database/mysql"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/messaging"
+	"github.com/josimarz/fc-eda-challenge/internal/infra/webserver"
+	"github.com/josimarz/fc-eda-challenge/internal/usecase"
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/josimarz/fc-eda-challenge/pkg/events/codec"
+	"github.com/josimarz/fc-eda-challenge/pkg/eventstore"
+	"github.com/josimarz/fc-eda-challenge/pkg/projection"
+)
+
+// WalletCore is the walletcore service: the HTTP API, the consumer of the
+// balances topic, the payment request expirer and the read model projections.
+type WalletCore struct {
+	config                       *configs.Config
+	db                           *sql.DB
+	codecs                       *codec.Registry
+	schemas                      *events.SchemaRegistry
+	eventDispatcher              *events.EventDispatcher
+	applyTransferUseCase         *usecase.ApplyTransferUseCase
+	expirePaymentRequestsUseCase *usecase.ExpirePaymentRequestsUseCase
+	handlers                     []webserver.Handler
+	projections                  []*projection.Runner
+}
+
+func NewWalletCore(config *configs.Config, db *sql.DB, codecs *codec.Registry, publisher messaging.Publisher) (*WalletCore, error) {
+	s := &WalletCore{
+		config:          config,
+		db:              db,
+		codecs:          codecs,
+		schemas:         eventhandling.NewSchemaRegistry(),
+		eventDispatcher: newEventDispatcher("walletcore"),
+	}
+	err := errors.Join(
+		s.eventDispatcher.Register("#", eventhandling.NewAuditHandler("walletcore")),
+		s.eventDispatcher.Register("#", eventstore.NewRecorder(mysql.NewEventStore(db), "walletcore", eventstore.ByAggregate(eventhandling.PartitionKey)), recording...),
+		events.Subscribe[eventhandling.TransactionCreatedPayload](s.eventDispatcher, eventhandling.TransactionCreated, eventhandling.NewTransactionCreatedHandler(publisher), publishing...),
+		events.Subscribe[eventhandling.PaymentRequestPayload](s.eventDispatcher, "payment_request.*", eventhandling.NewPaymentRequestChangedHandler(publisher), publishing...),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	customerGateway := mysql.NewCustomerGateway(db)
+	accountGateway := newAccountGateway(config, db, "walletcore")
+	keyGateway := mysql.NewKeyGateway(db)
+	paymentRequestGateway := mysql.NewPaymentRequestGateway(db)
+
+	s.applyTransferUseCase = usecase.NewApplyTransferUseCase(accountGateway, mysql.NewProcessedMessageGateway(db), mysql.NewTransactor(db))
+	s.expirePaymentRequestsUseCase = usecase.NewExpirePaymentRequestsUseCase(paymentRequestGateway, s.eventDispatcher)
+	s.handlers = []webserver.Handler{
+		webserver.NewCreateCustomerHandler(usecase.NewCreateCustomerUseCase(customerGateway)),
+		webserver.NewFindCustomerHandler(usecase.NewFindCustomerUseCase(customerGateway)),
+		webserver.NewListCustomersHandler(usecase.NewListCustomersUseCase(customerGateway)),
+		webserver.NewUpdateCustomerHandler(usecase.NewUpdateCustomerUseCase(customerGateway)),
+		webserver.NewDeleteCustomerHandler(usecase.NewDeleteCustomerUseCase(customerGateway)),
+		webserver.NewCreateAccountHandler(usecase.NewCreateAccountUseCase(accountGateway, customerGateway)),
+		webserver.NewListCustomerAccountsHandler(usecase.NewListCustomerAccountsUseCase(accountGateway, customerGateway)),
+		webserver.NewDepositHandler(usecase.NewDepositUseCase(accountGateway)),
+		webserver.NewWithdrawHandler(usecase.NewWithdrawUseCase(accountGateway)),
+		webserver.NewShowAccountBalanceHandler(usecase.NewShowAccountBalanceUseCase(accountGateway)),
+		webserver.NewRegisterKeyHandler(usecase.NewRegisterKeyUseCase(keyGateway, accountGateway, customerGateway)),
+		webserver.NewListCustomerKeysHandler(usecase.NewListCustomerKeysUseCase(keyGateway, customerGateway)),
+		webserver.NewDeleteKeyHandler(usecase.NewDeleteKeyUseCase(keyGateway, customerGateway)),
+		webserver.NewCreatePaymentRequestHandler(usecase.NewCreatePaymentRequestUseCase(paymentRequestGateway, accountGateway, keyGateway, s.eventDispatcher)),
+		webserver.NewListPaymentRequestsHandler(usecase.NewListPaymentRequestsUseCase(paymentRequestGateway, customerGateway)),
+		webserver.NewAcceptPaymentRequestHandler(usecase.NewAcceptPaymentRequestUseCase(paymentRequestGateway, s.eventDispatcher)),
+		webserver.NewDeclinePaymentRequestHandler(usecase.NewDeclinePaymentRequestUseCase(paymentRequestGateway, s.eventDispatcher)),
+		webserver.NewCreateTransactionHandler(s.eventDispatcher, usecase.NewResolveKeyUseCase(keyGateway)),
+		webserver.NewMetricsHandler(),
+	}
+
+	// customer_balance is built from the account.* events, which are only
+	// recorded when the accounts are event sourced.
+	if config.AccountStore == "eventsourced" {
+		checkpoints := mysql.NewProjectionCheckpointStore(db)
+		s.projections = []*projection.Runner{
+			projection.NewRunner(mysql.NewCustomerBalanceProjection(db), checkpoints),
+		}
+	}
+	return s, nil
+}
+
+// HandleBalancesMessage applies the transfer carried by a balances message to
+// both accounts.
+func (s *WalletCore) HandleBalancesMessage(ctx context.Context, message *messaging.Message) error {
+	envelope, err := messaging.DecodeMessage(message, s.codecs)
+	if err != nil {
+		return events.Permanent(err)
+	}
+	if err := s.schemas.Upcast(envelope); err != nil {
+		return events.Permanent(err)
+	}
+	output := usecase.CreateTransactionOutput{}
+	if err := envelope.Decode(&output); err != nil {
+		return events.Permanent(err)
+	}
+	input := &usecase.ApplyTransferInput{
+		Reference: envelope.Id,
+		From:      output.From.Id,
+		To:        output.To.Id,
+		Amount:    output.Amount,
+	}
+	if _, err := s.applyTransferUseCase.Execute(envelope.Context(ctx), input); err != nil {
+		return classify(err)
+	}
+	return nil
+}
+
+func (s *WalletCore) StartPaymentRequestExpirer() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		output, err := s.expirePaymentRequestsUseCase.Execute(context.Background(), &usecase.ExpirePaymentRequestsInput{Now: now})
+		if err != nil {
+			log.Println(err.Error())
+			continue
+		}
+		if output.Expired > 0 {
+			fmt.Printf("[Payment Requests] %d expired\n", output.Expired)
+		}
+	}
+}
+
+func (s *WalletCore) StartProjections() {
+	if len(s.projections) == 0 {
+		fmt.Println(`[Projections] Disabled, customer_balance requires ACCOUNT_STORE="eventsourced"`)
+		return
+	}
+	store := mysql.NewEventStore(s.db)
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		for _, runner := range s.projections {
+			applied, err := runner.CatchUp(context.Background(), store)
+			if err != nil {
+				log.Println(err.Error())
+				continue
+			}
+			if applied > 0 {
+				fmt.Printf("[%s] %d events applied\n", runner.Name(), applied)
+			}
+		}
+	}
+}
+
+// StartServer serves the HTTP API until ctx is done and then shuts it down.
+func (s *WalletCore) StartServer(ctx context.Context) error {
+	server := webserver.NewServer(s.config.Port)
+	for _, handler := range s.handlers {
+		server.AddHandler(handler)
+	}
+
+	ch := make(chan error, 1)
+	go func() {
+		fmt.Printf("[Web Server] Starting on port %s\n", s.config.Port)
+		if err := server.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			ch <- err
+		}
+	}()
+	select {
+	case err := <-ch:
+		return err
+	case <-ctx.Done():
+		fmt.Println("[Web Server] Shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
+
+func (s *WalletCore) Shutdown(ctx context.Context) error {
+	return s.eventDispatcher.Shutdown(ctx)
+}
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"time"
 
 	"github.com/josimarz/fc-eda-challenge/internal/entity"
@@ -28,13 +29,13 @@ func NewCreateAccountUseCase(accountGateway gateway.AccountGateway, customerGate
 	return &CreateAccountUseCase{accountGateway, customerGateway}
 }
 
-func (uc *CreateAccountUseCase) Execute(input CreateAccountInput) (*CreateAccountOutput, error) {
-	customer, err := uc.customerGateway.FindById(input.CustomerId)
+func (uc *CreateAccountUseCase) Execute(ctx context.Context, input CreateAccountInput) (*CreateAccountOutput, error) {
+	customer, err := uc.customerGateway.FindById(ctx, input.CustomerId)
 	if err != nil {
 		return nil, err
 	}
 	account := entity.NewAccount(customer)
-	if err := uc.accountGateway.Create(account); err != nil {
+	if err := uc.accountGateway.Create(ctx, account); err != nil {
 		return nil, err
 	}
 	return &CreateAccountOutput{
@@ -76,12 +77,12 @@ func NewListCustomerAccountsUseCase(accountGateway gateway.AccountGateway, custo
 	return &ListCustomerAccountsUseCase{accountGateway, customerGateway}
 }
 
-func (uc *ListCustomerAccountsUseCase) Execute(input ListCustomerAccountsInput) (*ListCustomerAccountsOutput, error) {
-	customer, err := uc.customerGateway.FindById(input.CustomerId)
+func (uc *ListCustomerAccountsUseCase) Execute(ctx context.Context, input ListCustomerAccountsInput) (*ListCustomerAccountsOutput, error) {
+	customer, err := uc.customerGateway.FindById(ctx, input.CustomerId)
 	if err != nil {
 		return nil, err
 	}
-	accounts, err := uc.accountGateway.FindByCustomer(customer)
+	accounts, err := uc.accountGateway.FindByCustomer(ctx, customer)
 	if err != nil {
 		return nil, err
 	}
@@ -118,15 +119,15 @@ func NewDepositUseCase(accountGateway gateway.AccountGateway) *DepositUseCase {
 	return &DepositUseCase{accountGateway}
 }
 
-func (uc *DepositUseCase) Execute(input *DepositInput) (*DepositOutput, error) {
-	account, err := uc.accountGateway.FindById(input.Id)
+func (uc *DepositUseCase) Execute(ctx context.Context, input *DepositInput) (*DepositOutput, error) {
+	account, err := uc.accountGateway.FindById(ctx, input.Id)
 	if err != nil {
 		return nil, err
 	}
 	if err := account.Deposit(input.Amount); err != nil {
 		return nil, err
 	}
-	if err := uc.accountGateway.Update(account); err != nil {
+	if err := uc.accountGateway.Update(ctx, account); err != nil {
 		return nil, err
 	}
 	return &DepositOutput{
@@ -165,15 +166,15 @@ func NewWithdrawUseCase(accountGateway gateway.AccountGateway) *WithdrawUseCase
 	return &WithdrawUseCase{accountGateway}
 }
 
-func (uc *WithdrawUseCase) Execute(input *WithdrawInput) (*WithdrawOutput, error) {
-	account, err := uc.accountGateway.FindById(input.Id)
+func (uc *WithdrawUseCase) Execute(ctx context.Context, input *WithdrawInput) (*WithdrawOutput, error) {
+	account, err := uc.accountGateway.FindById(ctx, input.Id)
 	if err != nil {
 		return nil, err
 	}
 	if err := account.Withdraw(input.Amount); err != nil {
 		return nil, err
 	}
-	if err := uc.accountGateway.Update(account); err != nil {
+	if err := uc.accountGateway.Update(ctx, account); err != nil {
 		return nil, err
 	}
 	return &WithdrawOutput{
@@ -191,6 +192,67 @@ func (uc *WithdrawUseCase) Execute(input *WithdrawInput) (*WithdrawOutput, error
 	}, nil
 }
 
+type ApplyTransferInput struct {
+	Reference string
+	From      string
+	To        string
+	Amount    float64
+}
+
+type ApplyTransferOutput struct {
+	Applied bool `json:"applied"`
+}
+
+type ApplyTransferUseCase struct {
+	accountGateway          gateway.AccountGateway
+	processedMessageGateway gateway.ProcessedMessageGateway
+	transactor              gateway.Transactor
+}
+
+func NewApplyTransferUseCase(
+	accountGateway gateway.AccountGateway,
+	processedMessageGateway gateway.ProcessedMessageGateway,
+	transactor gateway.Transactor,
+) *ApplyTransferUseCase {
+	return &ApplyTransferUseCase{accountGateway, processedMessageGateway, transactor}
+}
+
+func (uc *ApplyTransferUseCase) Execute(ctx context.Context, input *ApplyTransferInput) (*ApplyTransferOutput, error) {
+	output := &ApplyTransferOutput{}
+	err := uc.transactor.Transact(ctx, func(ctx context.Context) error {
+		first, err := uc.processedMessageGateway.MarkProcessed(ctx, input.Reference)
+		if err != nil || !first {
+			return err
+		}
+		to, err := uc.accountGateway.FindById(ctx, input.To)
+		if err != nil {
+			return err
+		}
+		if err := to.Credit(input.Amount, input.Reference); err != nil {
+			return err
+		}
+		if err := uc.accountGateway.Update(ctx, to); err != nil {
+			return err
+		}
+		from, err := uc.accountGateway.FindById(ctx, input.From)
+		if err != nil {
+			return err
+		}
+		if err := from.Debit(input.Amount, input.Reference); err != nil {
+			return err
+		}
+		if err := uc.accountGateway.Update(ctx, from); err != nil {
+			return err
+		}
+		output.Applied = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
 type ShowAccountBalanceInput struct {
 	Id string
 }
@@ -210,8 +272,8 @@ func NewShowAccountBalanceUseCase(accountGateway gateway.AccountGateway) *ShowAc
 	return &ShowAccountBalanceUseCase{accountGateway}
 }
 
-func (uc *ShowAccountBalanceUseCase) Execute(input *ShowAccountBalanceInput) (*ShowAccountBalanceOutput, error) {
-	account, err := uc.accountGateway.FindById(input.Id)
+func (uc *ShowAccountBalanceUseCase) Execute(ctx context.Context, input *ShowAccountBalanceInput) (*ShowAccountBalanceOutput, error) {
+	account, err := uc.accountGateway.FindById(ctx, input.Id)
 	if err != nil {
 		return nil, err
 	}
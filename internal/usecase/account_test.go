@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/josimarz/fc-eda-challenge/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AccountTestSuite struct {
+	suite.Suite
+	from                        *entity.Account
+	to                          *entity.Account
+	mockAccountGateway          *MockAccountGateway
+	mockProcessedMessageGateway *MockProcessedMessageGateway
+	mockTransactor              *MockTransactor
+	applyTransferUseCase        *ApplyTransferUseCase
+}
+
+func (suite *AccountTestSuite) SetupTest() {
+	customer, _ := entity.NewCustomer("Gustavo Kuerten", "guga@tennis.com")
+	suite.from = entity.NewAccount(customer)
+	suite.from.Deposit(100.0)
+	customer, _ = entity.NewCustomer("Ana Ivanovic", "ivanovic@wta.com")
+	suite.to = entity.NewAccount(customer)
+	suite.mockAccountGateway = &MockAccountGateway{}
+	suite.mockProcessedMessageGateway = &MockProcessedMessageGateway{}
+	suite.mockTransactor = &MockTransactor{}
+	suite.mockTransactor.On("Transact").Return()
+	suite.applyTransferUseCase = NewApplyTransferUseCase(suite.mockAccountGateway, suite.mockProcessedMessageGateway, suite.mockTransactor)
+}
+
+func (suite *AccountTestSuite) TestApplyTransferUseCase_Execute() {
+	suite.mockProcessedMessageGateway.On("MarkProcessed", "message-1").Return(true, nil)
+	suite.mockAccountGateway.On("FindById", suite.from.Id).Return(suite.from, nil)
+	suite.mockAccountGateway.On("FindById", suite.to.Id).Return(suite.to, nil)
+	suite.mockAccountGateway.On("Update", mock.Anything).Return(nil)
+	input := &ApplyTransferInput{Reference: "message-1", From: suite.from.Id, To: suite.to.Id, Amount: 30.0}
+	output, err := suite.applyTransferUseCase.Execute(context.Background(), input)
+
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), output.Applied)
+	assert.Equal(suite.T(), 70.0, suite.from.Balance)
+	assert.Equal(suite.T(), 30.0, suite.to.Balance)
+	suite.mockAccountGateway.AssertNumberOfCalls(suite.T(), "Update", 2)
+	suite.mockTransactor.AssertNumberOfCalls(suite.T(), "Transact", 1)
+}
+
+func (suite *AccountTestSuite) TestApplyTransferUseCase_Execute_WithProcessedMessage() {
+	suite.mockProcessedMessageGateway.On("MarkProcessed", "message-1").Return(false, nil)
+	input := &ApplyTransferInput{Reference: "message-1", From: suite.from.Id, To: suite.to.Id, Amount: 30.0}
+	output, err := suite.applyTransferUseCase.Execute(context.Background(), input)
+
+	assert.Nil(suite.T(), err)
+	assert.False(suite.T(), output.Applied)
+	suite.mockAccountGateway.AssertNotCalled(suite.T(), "FindById", mock.Anything)
+	suite.mockAccountGateway.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+
+func (suite *AccountTestSuite) TestApplyTransferUseCase_Execute_WithInsufficientFunds() {
+	suite.mockProcessedMessageGateway.On("MarkProcessed", "message-1").Return(true, nil)
+	suite.mockAccountGateway.On("FindById", suite.from.Id).Return(suite.from, nil)
+	suite.mockAccountGateway.On("FindById", suite.to.Id).Return(suite.to, nil)
+	suite.mockAccountGateway.On("Update", mock.Anything).Return(nil)
+	input := &ApplyTransferInput{Reference: "message-1", From: suite.from.Id, To: suite.to.Id, Amount: 300.0}
+	output, err := suite.applyTransferUseCase.Execute(context.Background(), input)
+
+	assert.Nil(suite.T(), output)
+	assert.EqualError(suite.T(), err, "unable to debit: insufficient funds")
+	suite.mockAccountGateway.AssertNumberOfCalls(suite.T(), "Update", 1)
+}
+
+func TestAccountTestSuite(t *testing.T) {
+	suite.Run(t, new(AccountTestSuite))
+}
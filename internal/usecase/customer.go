@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"time"
 
 	"github.com/josimarz/fc-eda-challenge/internal/entity"
@@ -10,12 +11,16 @@ import (
 type CreateCustomerInput struct {
 	Name  string `json:"name"`
 	Email string `json:"email"`
+	Phone string `json:"phone"`
+	TaxId string `json:"taxId"`
 }
 
 type CreateCustomerOutput struct {
 	Id        string    `json:"id"`
 	Name      string    `json:"name"`
 	Email     string    `json:"email"`
+	Phone     string    `json:"phone,omitempty"`
+	TaxId     string    `json:"taxId,omitempty"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
@@ -28,18 +33,23 @@ func NewCreateCustomerUseCase(customerGateway gateway.CustomerGateway) *CreateCu
 	return &CreateCustomerUseCase{customerGateway}
 }
 
-func (uc *CreateCustomerUseCase) Execute(input *CreateCustomerInput) (*CreateCustomerOutput, error) {
+func (uc *CreateCustomerUseCase) Execute(ctx context.Context, input *CreateCustomerInput) (*CreateCustomerOutput, error) {
 	customer, err := entity.NewCustomer(input.Name, input.Email)
 	if err != nil {
 		return nil, err
 	}
-	if err := uc.customerGateway.Create(customer); err != nil {
+	if err := customer.UpdateDocuments(input.Phone, input.TaxId); err != nil {
+		return nil, err
+	}
+	if err := uc.customerGateway.Create(ctx, customer); err != nil {
 		return nil, err
 	}
 	return &CreateCustomerOutput{
 		Id:        customer.Id,
 		Name:      customer.Name,
 		Email:     customer.Email,
+		Phone:     customer.Phone,
+		TaxId:     customer.TaxId,
 		CreatedAt: customer.CreatedAt,
 		UpdatedAt: customer.UpdatedAt,
 	}, nil
@@ -53,6 +63,8 @@ type FindCustomerOutput struct {
 	Id        string    `json:"id"`
 	Name      string    `json:"name"`
 	Email     string    `json:"email"`
+	Phone     string    `json:"phone,omitempty"`
+	TaxId     string    `json:"taxId,omitempty"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
@@ -65,8 +77,8 @@ func NewFindCustomerUseCase(customerGateway gateway.CustomerGateway) *FindCustom
 	return &FindCustomerUseCase{customerGateway}
 }
 
-func (uc *FindCustomerUseCase) Execute(input *FindCustomerInput) (*FindCustomerOutput, error) {
-	customer, err := uc.customerGateway.FindById(input.Id)
+func (uc *FindCustomerUseCase) Execute(ctx context.Context, input *FindCustomerInput) (*FindCustomerOutput, error) {
+	customer, err := uc.customerGateway.FindById(ctx, input.Id)
 	if err != nil {
 		return nil, err
 	}
@@ -74,6 +86,8 @@ func (uc *FindCustomerUseCase) Execute(input *FindCustomerInput) (*FindCustomerO
 		Id:        customer.Id,
 		Name:      customer.Name,
 		Email:     customer.Email,
+		Phone:     customer.Phone,
+		TaxId:     customer.TaxId,
 		CreatedAt: customer.CreatedAt,
 		UpdatedAt: customer.UpdatedAt,
 	}, nil
@@ -85,6 +99,8 @@ type CustomerOutput struct {
 	Id        string    `json:"id"`
 	Name      string    `json:"name"`
 	Email     string    `json:"email"`
+	Phone     string    `json:"phone,omitempty"`
+	TaxId     string    `json:"taxId,omitempty"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
@@ -101,8 +117,8 @@ func NewListCustomersUseCase(customerGateway gateway.CustomerGateway) *ListCusto
 	return &ListCustomersUseCase{customerGateway}
 }
 
-func (uc *ListCustomersUseCase) Execute(input *ListCustomersInput) (*ListCustomersOutput, error) {
-	customers, err := uc.customerGateway.FindAll()
+func (uc *ListCustomersUseCase) Execute(ctx context.Context, input *ListCustomersInput) (*ListCustomersOutput, error) {
+	customers, err := uc.customerGateway.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -112,6 +128,8 @@ func (uc *ListCustomersUseCase) Execute(input *ListCustomersInput) (*ListCustome
 			Id:        customer.Id,
 			Name:      customer.Name,
 			Email:     customer.Email,
+			Phone:     customer.Phone,
+			TaxId:     customer.TaxId,
 			CreatedAt: customer.CreatedAt,
 			UpdatedAt: customer.UpdatedAt,
 		}
@@ -120,16 +138,22 @@ func (uc *ListCustomersUseCase) Execute(input *ListCustomersInput) (*ListCustome
 	return output, nil
 }
 
+// UpdateCustomerInput leaves Phone and TaxId untouched when they are nil, so
+// an update that omits them does not clear the values behind registered keys.
 type UpdateCustomerInput struct {
 	Id    string
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	Name  string  `json:"name"`
+	Email string  `json:"email"`
+	Phone *string `json:"phone"`
+	TaxId *string `json:"taxId"`
 }
 
 type UpdateCustomerOutput struct {
 	Id        string    `json:"id"`
 	Name      string    `json:"name"`
 	Email     string    `json:"email"`
+	Phone     string    `json:"phone,omitempty"`
+	TaxId     string    `json:"taxId,omitempty"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
@@ -142,21 +166,33 @@ func NewUpdateCustomerUseCase(customerGateway gateway.CustomerGateway) *UpdateCu
 	return &UpdateCustomerUseCase{customerGateway}
 }
 
-func (uc *UpdateCustomerUseCase) Execute(input *UpdateCustomerInput) (*UpdateCustomerOutput, error) {
-	customer, err := uc.customerGateway.FindById(input.Id)
+func (uc *UpdateCustomerUseCase) Execute(ctx context.Context, input *UpdateCustomerInput) (*UpdateCustomerOutput, error) {
+	customer, err := uc.customerGateway.FindById(ctx, input.Id)
 	if err != nil {
 		return nil, err
 	}
 	if err := customer.Update(input.Name, input.Email); err != nil {
 		return nil, err
 	}
-	if err := uc.customerGateway.Update(customer); err != nil {
+	phone, taxId := customer.Phone, customer.TaxId
+	if input.Phone != nil {
+		phone = *input.Phone
+	}
+	if input.TaxId != nil {
+		taxId = *input.TaxId
+	}
+	if err := customer.UpdateDocuments(phone, taxId); err != nil {
+		return nil, err
+	}
+	if err := uc.customerGateway.Update(ctx, customer); err != nil {
 		return nil, err
 	}
 	return &UpdateCustomerOutput{
 		Id:        customer.Id,
 		Name:      customer.Name,
 		Email:     customer.Email,
+		Phone:     customer.Phone,
+		TaxId:     customer.TaxId,
 		CreatedAt: customer.CreatedAt,
 		UpdatedAt: customer.UpdatedAt,
 	}, nil
@@ -170,6 +206,8 @@ type DeleteCustomerOutput struct {
 	Id        string    `json:"id"`
 	Name      string    `json:"name"`
 	Email     string    `json:"email"`
+	Phone     string    `json:"phone,omitempty"`
+	TaxId     string    `json:"taxId,omitempty"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
@@ -182,18 +220,20 @@ func NewDeleteCustomerUseCase(customerGateway gateway.CustomerGateway) *DeleteCu
 	return &DeleteCustomerUseCase{customerGateway}
 }
 
-func (uc *DeleteCustomerUseCase) Execute(input *DeleteCustomerInput) (*DeleteCustomerOutput, error) {
-	customer, err := uc.customerGateway.FindById(input.Id)
+func (uc *DeleteCustomerUseCase) Execute(ctx context.Context, input *DeleteCustomerInput) (*DeleteCustomerOutput, error) {
+	customer, err := uc.customerGateway.FindById(ctx, input.Id)
 	if err != nil {
 		return nil, err
 	}
-	if err := uc.customerGateway.Delete(customer); err != nil {
+	if err := uc.customerGateway.Delete(ctx, customer); err != nil {
 		return nil, err
 	}
 	return &DeleteCustomerOutput{
 		Id:        customer.Id,
 		Name:      customer.Name,
 		Email:     customer.Email,
+		Phone:     customer.Phone,
+		TaxId:     customer.TaxId,
 		CreatedAt: customer.CreatedAt,
 		UpdatedAt: customer.UpdatedAt,
 	}, nil
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -40,7 +41,7 @@ func (suite *CustomerTestSuite) TestCreateCustomerUseCase_Execute() {
 		Name:  name,
 		Email: email,
 	}
-	output, err := suite.createCustomerUseCase.Execute(input)
+	output, err := suite.createCustomerUseCase.Execute(context.Background(), input)
 
 	assert.Nil(suite.T(), err)
 	assert.NotNil(suite.T(), output)
@@ -55,7 +56,7 @@ func (suite *CustomerTestSuite) TestCreateCustomerUseCase_Execute_WithInvalidCus
 		Name:  "",
 		Email: "josimarz@yahoo.com.br",
 	}
-	output, err := suite.createCustomerUseCase.Execute(input)
+	output, err := suite.createCustomerUseCase.Execute(context.Background(), input)
 
 	assert.Nil(suite.T(), output)
 	assert.NotNil(suite.T(), err)
@@ -69,7 +70,7 @@ func (suite *CustomerTestSuite) TestCreateCustomerUseCase_Execute_WithGatewayErr
 		Name:  "Josimar Zimermann",
 		Email: "josimarz@yahoo.com.br",
 	}
-	output, err := suite.createCustomerUseCase.Execute(input)
+	output, err := suite.createCustomerUseCase.Execute(context.Background(), input)
 
 	assert.Nil(suite.T(), output)
 	assert.NotNil(suite.T(), err)
@@ -91,7 +92,7 @@ func (suite *CustomerTestSuite) TestFindCustomerUseCase_Execute() {
 	suite.mockCustomerGateway.On("FindById", mock.Anything).Return(customer, nil)
 
 	input := &FindCustomerInput{Id: ""}
-	output, err := suite.findCustomerUseCase.Execute(input)
+	output, err := suite.findCustomerUseCase.Execute(context.Background(), input)
 
 	assert.Nil(suite.T(), err)
 	assert.NotNil(suite.T(), output)
@@ -107,7 +108,7 @@ func (suite *CustomerTestSuite) TestFindCustomerUseCase_Execute() {
 func (suite *CustomerTestSuite) TestFindCustomerUseCase_Execute_WithGatewayError() {
 	suite.mockCustomerGateway.On("FindById", mock.Anything).Return(&entity.Customer{}, errors.New("unable to find customer"))
 	input := &FindCustomerInput{Id: ""}
-	output, err := suite.findCustomerUseCase.Execute(input)
+	output, err := suite.findCustomerUseCase.Execute(context.Background(), input)
 
 	assert.Nil(suite.T(), output)
 	assert.NotNil(suite.T(), err)
@@ -147,7 +148,7 @@ func (suite *CustomerTestSuite) TestListCustomersUseCase_Execute() {
 		},
 	}
 	suite.mockCustomerGateway.On("FindAll", mock.Anything).Return(customers, nil)
-	output, err := suite.listCustomersUseCase.Execute(&ListCustomersInput{})
+	output, err := suite.listCustomersUseCase.Execute(context.Background(), &ListCustomersInput{})
 
 	assert.Nil(suite.T(), err)
 	assert.NotNil(suite.T(), output)
@@ -158,7 +159,7 @@ func (suite *CustomerTestSuite) TestListCustomersUseCase_Execute() {
 
 func (suite *CustomerTestSuite) TestListCustomersUseCase_Execute_WithGatewayError() {
 	suite.mockCustomerGateway.On("FindAll", mock.Anything).Return([]*entity.Customer{}, errors.New("unable to find customers"))
-	output, err := suite.listCustomersUseCase.Execute(&ListCustomersInput{})
+	output, err := suite.listCustomersUseCase.Execute(context.Background(), &ListCustomersInput{})
 
 	assert.Nil(suite.T(), output)
 	assert.NotNil(suite.T(), err)
@@ -184,7 +185,7 @@ func (suite *CustomerTestSuite) TestUpdateCustomerUseCase_Execute() {
 		Name:  "Ana Ivanovic",
 		Email: "ivanovic@wta.com",
 	}
-	output, err := suite.updateCustomerUseCase.Execute(input)
+	output, err := suite.updateCustomerUseCase.Execute(context.Background(), input)
 
 	assert.Nil(suite.T(), err)
 	assert.NotNil(suite.T(), output)
@@ -198,6 +199,25 @@ func (suite *CustomerTestSuite) TestUpdateCustomerUseCase_Execute() {
 	suite.mockCustomerGateway.AssertNumberOfCalls(suite.T(), "Update", 1)
 }
 
+func (suite *CustomerTestSuite) TestUpdateCustomerUseCase_Execute_WithDocuments() {
+	customer, _ := entity.NewCustomer("Josimar Zimermann", "josimarz@yahoo.com.br")
+	customer.UpdateDocuments("+55 (47) 99999-0000", "123.456.789-09")
+	suite.mockCustomerGateway.On("FindById", mock.Anything).Return(customer, nil)
+	suite.mockCustomerGateway.On("Update", mock.Anything).Return(nil)
+	phone := "+55 (47) 98888-0000"
+	input := &UpdateCustomerInput{
+		Id:    customer.Id,
+		Name:  "Ana Ivanovic",
+		Email: "ivanovic@wta.com",
+		Phone: &phone,
+	}
+	output, err := suite.updateCustomerUseCase.Execute(context.Background(), input)
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), entity.NormalizeKeyValue(entity.PhoneKey, phone), output.Phone)
+	assert.Equal(suite.T(), "12345678909", output.TaxId)
+}
+
 func (suite *CustomerTestSuite) TestUpdateCustomerUseCase_Execute_WithFindError() {
 	suite.mockCustomerGateway.On("FindById", mock.Anything).Return(&entity.Customer{}, errors.New("unable to find customer"))
 	input := &UpdateCustomerInput{
@@ -205,7 +225,7 @@ func (suite *CustomerTestSuite) TestUpdateCustomerUseCase_Execute_WithFindError(
 		Name:  "Josimar Zimermann",
 		Email: "josimarz@yahoo.com.br",
 	}
-	output, err := suite.updateCustomerUseCase.Execute(input)
+	output, err := suite.updateCustomerUseCase.Execute(context.Background(), input)
 
 	assert.Nil(suite.T(), output)
 	assert.NotNil(suite.T(), err)
@@ -230,7 +250,7 @@ func (suite *CustomerTestSuite) TestUpdateCustomerUseCase_Execute_WithEmptyName(
 		Name:  "",
 		Email: "josimarz@yahoo.com.br",
 	}
-	output, err := suite.updateCustomerUseCase.Execute(input)
+	output, err := suite.updateCustomerUseCase.Execute(context.Background(), input)
 
 	assert.Nil(suite.T(), output)
 	assert.NotNil(suite.T(), err)
@@ -256,7 +276,7 @@ func (suite *CustomerTestSuite) TestUpdateCustomerUseCase_Execute_WithUpdateErro
 		Name:  "Ana Ivanovic",
 		Email: "ivanovic@wta.com",
 	}
-	output, err := suite.updateCustomerUseCase.Execute(input)
+	output, err := suite.updateCustomerUseCase.Execute(context.Background(), input)
 
 	assert.Nil(suite.T(), output)
 	assert.NotNil(suite.T(), err)
@@ -279,7 +299,7 @@ func (suite *CustomerTestSuite) TestDeleteCustomerUseCase_Execute() {
 	suite.mockCustomerGateway.On("FindById", mock.Anything).Return(customer, nil)
 	suite.mockCustomerGateway.On("Delete", mock.Anything).Return(nil)
 	input := &DeleteCustomerInput{Id: customer.Id}
-	output, err := suite.deleteCustomerUseCase.Execute(input)
+	output, err := suite.deleteCustomerUseCase.Execute(context.Background(), input)
 
 	assert.Nil(suite.T(), err)
 	assert.NotNil(suite.T(), output)
@@ -296,7 +316,7 @@ func (suite *CustomerTestSuite) TestDeleteCustomerUseCase_Execute() {
 func (suite *CustomerTestSuite) TestDeleteCustomerUseCase_Execute_WithFindError() {
 	suite.mockCustomerGateway.On("FindById", mock.Anything).Return(&entity.Customer{}, errors.New("unable to find customer"))
 	input := &DeleteCustomerInput{Id: ""}
-	output, err := suite.deleteCustomerUseCase.Execute(input)
+	output, err := suite.deleteCustomerUseCase.Execute(context.Background(), input)
 
 	assert.Nil(suite.T(), output)
 	assert.NotNil(suite.T(), err)
@@ -318,7 +338,7 @@ func (suite *CustomerTestSuite) TestDeleteCustomerUseCase_Execute_WithDeleteErro
 	suite.mockCustomerGateway.On("FindById", mock.Anything).Return(customer, nil)
 	suite.mockCustomerGateway.On("Delete", mock.Anything).Return(errors.New("unable to delete customer"))
 	input := &DeleteCustomerInput{Id: customer.Id}
-	output, err := suite.deleteCustomerUseCase.Execute(input)
+	output, err := suite.deleteCustomerUseCase.Execute(context.Background(), input)
 
 	assert.Nil(suite.T(), output)
 	assert.NotNil(suite.T(), err)
@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/josimarz/fc-eda-challenge/internal/entity"
+	"github.com/josimarz/fc-eda-challenge/internal/gateway"
+)
+
+var (
+	ErrKeyNotFound = errors.New("key not found")
+	ErrInvalidKey  = errors.New("invalid key")
+)
+
+var customerFields = map[entity.KeyType]string{
+	entity.EmailKey: "email",
+	entity.PhoneKey: "phone",
+	entity.TaxIdKey: "tax id",
+}
+
+type KeyOutput struct {
+	Id        string    `json:"id"`
+	Type      string    `json:"type"`
+	Value     string    `json:"value"`
+	AccountId string    `json:"accountId"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type RegisterKeyInput struct {
+	CustomerId string
+	AccountId  string `json:"accountId"`
+	Type       string `json:"type"`
+	Value      string `json:"value"`
+}
+
+type RegisterKeyUseCase struct {
+	keyGateway      gateway.KeyGateway
+	accountGateway  gateway.AccountGateway
+	customerGateway gateway.CustomerGateway
+}
+
+func NewRegisterKeyUseCase(
+	keyGateway gateway.KeyGateway,
+	accountGateway gateway.AccountGateway,
+	customerGateway gateway.CustomerGateway,
+) *RegisterKeyUseCase {
+	return &RegisterKeyUseCase{keyGateway, accountGateway, customerGateway}
+}
+
+func (uc *RegisterKeyUseCase) Execute(ctx context.Context, input *RegisterKeyInput) (*KeyOutput, error) {
+	customer, err := uc.customerGateway.FindById(ctx, input.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+	account, err := uc.accountGateway.FindById(ctx, input.AccountId)
+	if err != nil {
+		return nil, err
+	}
+	if account.Customer == nil || account.Customer.Id != customer.Id {
+		return nil, errors.New("account does not belong to customer")
+	}
+	key, err := entity.NewKey(entity.KeyType(input.Type), input.Value, account)
+	if err != nil {
+		return nil, err
+	}
+	if !customer.Owns(key.Type, key.Value) {
+		return nil, fmt.Errorf("%s key does not match customer %s", key.Type, customerFields[key.Type])
+	}
+	existing, err := uc.keyGateway.FindByValue(ctx, key.Value)
+	if err != nil && !errors.Is(err, gateway.ErrNotFound) {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("key already registered")
+	}
+	if err := uc.keyGateway.Create(ctx, key); err != nil {
+		return nil, err
+	}
+	return newKeyOutput(key), nil
+}
+
+type ListCustomerKeysInput struct {
+	CustomerId string
+}
+
+type ListCustomerKeysOutput struct {
+	Keys []*KeyOutput `json:"keys"`
+}
+
+type ListCustomerKeysUseCase struct {
+	keyGateway      gateway.KeyGateway
+	customerGateway gateway.CustomerGateway
+}
+
+func NewListCustomerKeysUseCase(keyGateway gateway.KeyGateway, customerGateway gateway.CustomerGateway) *ListCustomerKeysUseCase {
+	return &ListCustomerKeysUseCase{keyGateway, customerGateway}
+}
+
+func (uc *ListCustomerKeysUseCase) Execute(ctx context.Context, input *ListCustomerKeysInput) (*ListCustomerKeysOutput, error) {
+	customer, err := uc.customerGateway.FindById(ctx, input.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := uc.keyGateway.FindByCustomer(ctx, customer)
+	if err != nil {
+		return nil, err
+	}
+	output := &ListCustomerKeysOutput{}
+	for _, key := range keys {
+		output.Keys = append(output.Keys, newKeyOutput(key))
+	}
+	return output, nil
+}
+
+type DeleteKeyInput struct {
+	CustomerId string
+	Value      string
+}
+
+type DeleteKeyUseCase struct {
+	keyGateway      gateway.KeyGateway
+	customerGateway gateway.CustomerGateway
+}
+
+func NewDeleteKeyUseCase(keyGateway gateway.KeyGateway, customerGateway gateway.CustomerGateway) *DeleteKeyUseCase {
+	return &DeleteKeyUseCase{keyGateway, customerGateway}
+}
+
+func (uc *DeleteKeyUseCase) Execute(ctx context.Context, input *DeleteKeyInput) (*KeyOutput, error) {
+	customer, err := uc.customerGateway.FindById(ctx, input.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+	_, value, err := entity.ParseKeyValue(input.Value)
+	if err != nil {
+		return nil, err
+	}
+	key, err := uc.keyGateway.FindByValue(ctx, value)
+	if err != nil {
+		return nil, err
+	}
+	if !key.IsOwnedBy(customer) {
+		return nil, errors.New("key does not belong to customer")
+	}
+	if err := uc.keyGateway.Delete(ctx, key); err != nil {
+		return nil, err
+	}
+	return newKeyOutput(key), nil
+}
+
+type ResolveKeyInput struct {
+	Value string
+}
+
+type ResolveKeyOutput struct {
+	AccountId string `json:"accountId"`
+}
+
+type ResolveKeyUseCase struct {
+	keyGateway gateway.KeyGateway
+}
+
+func NewResolveKeyUseCase(keyGateway gateway.KeyGateway) *ResolveKeyUseCase {
+	return &ResolveKeyUseCase{keyGateway}
+}
+
+func (uc *ResolveKeyUseCase) Execute(ctx context.Context, input *ResolveKeyInput) (*ResolveKeyOutput, error) {
+	_, value, err := entity.ParseKeyValue(input.Value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+	}
+	key, err := uc.keyGateway.FindByValue(ctx, value)
+	if errors.Is(err, gateway.ErrNotFound) {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ResolveKeyOutput{AccountId: key.Account.Id}, nil
+}
+
+func newKeyOutput(key *entity.Key) *KeyOutput {
+	return &KeyOutput{
+		Id:        key.Id,
+		Type:      string(key.Type),
+		Value:     key.Value,
+		AccountId: key.Account.Id,
+		CreatedAt: key.CreatedAt,
+		UpdatedAt: key.UpdatedAt,
+	}
+}
@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/josimarz/fc-eda-challenge/internal/entity"
+	"github.com/josimarz/fc-eda-challenge/internal/gateway"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type KeyTestSuite struct {
+	suite.Suite
+	customer                *entity.Customer
+	account                 *entity.Account
+	mockKeyGateway          *MockKeyGateway
+	mockAccountGateway      *MockAccountGateway
+	mockCustomerGateway     *MockCustomerGateway
+	registerKeyUseCase      *RegisterKeyUseCase
+	listCustomerKeysUseCase *ListCustomerKeysUseCase
+	deleteKeyUseCase        *DeleteKeyUseCase
+	resolveKeyUseCase       *ResolveKeyUseCase
+}
+
+func (suite *KeyTestSuite) SetupTest() {
+	suite.customer, _ = entity.NewCustomer("Josimar Zimermann", "josimarz@yahoo.com.br")
+	suite.customer.UpdateDocuments("+55 47 99999-0000", "123.456.789-09")
+	suite.account = entity.NewAccount(suite.customer)
+	suite.mockKeyGateway = &MockKeyGateway{}
+	suite.mockAccountGateway = &MockAccountGateway{}
+	suite.mockCustomerGateway = &MockCustomerGateway{}
+	suite.registerKeyUseCase = NewRegisterKeyUseCase(suite.mockKeyGateway, suite.mockAccountGateway, suite.mockCustomerGateway)
+	suite.listCustomerKeysUseCase = NewListCustomerKeysUseCase(suite.mockKeyGateway, suite.mockCustomerGateway)
+	suite.deleteKeyUseCase = NewDeleteKeyUseCase(suite.mockKeyGateway, suite.mockCustomerGateway)
+	suite.resolveKeyUseCase = NewResolveKeyUseCase(suite.mockKeyGateway)
+}
+
+func (suite *KeyTestSuite) TestRegisterKeyUseCase_Execute() {
+	suite.mockCustomerGateway.On("FindById", suite.customer.Id).Return(suite.customer, nil)
+	suite.mockAccountGateway.On("FindById", suite.account.Id).Return(suite.account, nil)
+	suite.mockKeyGateway.On("FindByValue", "+5547999990000").Return((*entity.Key)(nil), gateway.ErrNotFound)
+	suite.mockKeyGateway.On("Create", mock.Anything).Return(nil)
+	input := &RegisterKeyInput{
+		CustomerId: suite.customer.Id,
+		AccountId:  suite.account.Id,
+		Type:       "phone",
+		Value:      "+55 47 99999-0000",
+	}
+	output, err := suite.registerKeyUseCase.Execute(context.Background(), input)
+
+	assert.Nil(suite.T(), err)
+	assert.NotNil(suite.T(), output)
+	assert.Equal(suite.T(), "+5547999990000", output.Value)
+	assert.Equal(suite.T(), suite.account.Id, output.AccountId)
+	suite.mockKeyGateway.AssertNumberOfCalls(suite.T(), "Create", 1)
+}
+
+func (suite *KeyTestSuite) TestRegisterKeyUseCase_Execute_WithAccountOfAnotherCustomer() {
+	other, _ := entity.NewCustomer("Gustavo Kuerten", "guga@tennis.com")
+	account := entity.NewAccount(other)
+	suite.mockCustomerGateway.On("FindById", suite.customer.Id).Return(suite.customer, nil)
+	suite.mockAccountGateway.On("FindById", account.Id).Return(account, nil)
+	input := &RegisterKeyInput{
+		CustomerId: suite.customer.Id,
+		AccountId:  account.Id,
+		Type:       "random",
+	}
+	output, err := suite.registerKeyUseCase.Execute(context.Background(), input)
+
+	assert.Nil(suite.T(), output)
+	assert.EqualError(suite.T(), err, "account does not belong to customer")
+	suite.mockKeyGateway.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *KeyTestSuite) TestRegisterKeyUseCase_Execute_WithEmailOfAnotherCustomer() {
+	suite.mockCustomerGateway.On("FindById", suite.customer.Id).Return(suite.customer, nil)
+	suite.mockAccountGateway.On("FindById", suite.account.Id).Return(suite.account, nil)
+	input := &RegisterKeyInput{
+		CustomerId: suite.customer.Id,
+		AccountId:  suite.account.Id,
+		Type:       "email",
+		Value:      "guga@tennis.com",
+	}
+	output, err := suite.registerKeyUseCase.Execute(context.Background(), input)
+
+	assert.Nil(suite.T(), output)
+	assert.EqualError(suite.T(), err, "email key does not match customer email")
+}
+
+func (suite *KeyTestSuite) TestRegisterKeyUseCase_Execute_WithPhoneOfAnotherCustomer() {
+	suite.mockCustomerGateway.On("FindById", suite.customer.Id).Return(suite.customer, nil)
+	suite.mockAccountGateway.On("FindById", suite.account.Id).Return(suite.account, nil)
+	input := &RegisterKeyInput{
+		CustomerId: suite.customer.Id,
+		AccountId:  suite.account.Id,
+		Type:       "phone",
+		Value:      "+55 11 98888-0000",
+	}
+	output, err := suite.registerKeyUseCase.Execute(context.Background(), input)
+
+	assert.Nil(suite.T(), output)
+	assert.EqualError(suite.T(), err, "phone key does not match customer phone")
+	suite.mockKeyGateway.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *KeyTestSuite) TestRegisterKeyUseCase_Execute_WithLookupError() {
+	suite.mockCustomerGateway.On("FindById", suite.customer.Id).Return(suite.customer, nil)
+	suite.mockAccountGateway.On("FindById", suite.account.Id).Return(suite.account, nil)
+	suite.mockKeyGateway.On("FindByValue", "12345678909").Return((*entity.Key)(nil), errors.New("connection refused"))
+	input := &RegisterKeyInput{
+		CustomerId: suite.customer.Id,
+		AccountId:  suite.account.Id,
+		Type:       "taxId",
+		Value:      "123.456.789-09",
+	}
+	output, err := suite.registerKeyUseCase.Execute(context.Background(), input)
+
+	assert.Nil(suite.T(), output)
+	assert.EqualError(suite.T(), err, "connection refused")
+	suite.mockKeyGateway.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *KeyTestSuite) TestRegisterKeyUseCase_Execute_WithDuplicatedKey() {
+	existing, _ := entity.NewKey(entity.EmailKey, suite.customer.Email, suite.account)
+	suite.mockCustomerGateway.On("FindById", suite.customer.Id).Return(suite.customer, nil)
+	suite.mockAccountGateway.On("FindById", suite.account.Id).Return(suite.account, nil)
+	suite.mockKeyGateway.On("FindByValue", existing.Value).Return(existing, nil)
+	input := &RegisterKeyInput{
+		CustomerId: suite.customer.Id,
+		AccountId:  suite.account.Id,
+		Type:       "email",
+		Value:      suite.customer.Email,
+	}
+	output, err := suite.registerKeyUseCase.Execute(context.Background(), input)
+
+	assert.Nil(suite.T(), output)
+	assert.EqualError(suite.T(), err, "key already registered")
+	suite.mockKeyGateway.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *KeyTestSuite) TestListCustomerKeysUseCase_Execute() {
+	key, _ := entity.NewKey(entity.RandomKey, "", suite.account)
+	suite.mockCustomerGateway.On("FindById", suite.customer.Id).Return(suite.customer, nil)
+	suite.mockKeyGateway.On("FindByCustomer", suite.customer).Return([]*entity.Key{key}, nil)
+	output, err := suite.listCustomerKeysUseCase.Execute(context.Background(), &ListCustomerKeysInput{CustomerId: suite.customer.Id})
+
+	assert.Nil(suite.T(), err)
+	assert.Len(suite.T(), output.Keys, 1)
+	assert.Equal(suite.T(), key.Value, output.Keys[0].Value)
+}
+
+func (suite *KeyTestSuite) TestDeleteKeyUseCase_Execute_WithKeyOfAnotherCustomer() {
+	other, _ := entity.NewCustomer("Gustavo Kuerten", "guga@tennis.com")
+	key, _ := entity.NewKey(entity.EmailKey, other.Email, entity.NewAccount(other))
+	suite.mockCustomerGateway.On("FindById", suite.customer.Id).Return(suite.customer, nil)
+	suite.mockKeyGateway.On("FindByValue", key.Value).Return(key, nil)
+	output, err := suite.deleteKeyUseCase.Execute(context.Background(), &DeleteKeyInput{CustomerId: suite.customer.Id, Value: key.Value})
+
+	assert.Nil(suite.T(), output)
+	assert.EqualError(suite.T(), err, "key does not belong to customer")
+	suite.mockKeyGateway.AssertNotCalled(suite.T(), "Delete", mock.Anything)
+}
+
+func (suite *KeyTestSuite) TestResolveKeyUseCase_Execute() {
+	key, _ := entity.NewKey(entity.TaxIdKey, "12345678909", suite.account)
+	suite.mockKeyGateway.On("FindByValue", "12345678909").Return(key, nil)
+	output, err := suite.resolveKeyUseCase.Execute(context.Background(), &ResolveKeyInput{Value: "123.456.789-09"})
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), suite.account.Id, output.AccountId)
+}
+
+func (suite *KeyTestSuite) TestResolveKeyUseCase_Execute_WithUnknownKey() {
+	suite.mockKeyGateway.On("FindByValue", mock.Anything).Return((*entity.Key)(nil), gateway.ErrNotFound)
+	output, err := suite.resolveKeyUseCase.Execute(context.Background(), &ResolveKeyInput{Value: "guga@tennis.com"})
+
+	assert.Nil(suite.T(), output)
+	assert.ErrorIs(suite.T(), err, ErrKeyNotFound)
+}
+
+func (suite *KeyTestSuite) TestResolveKeyUseCase_Execute_WithInvalidKey() {
+	output, err := suite.resolveKeyUseCase.Execute(context.Background(), &ResolveKeyInput{Value: "+"})
+
+	assert.Nil(suite.T(), output)
+	assert.ErrorIs(suite.T(), err, ErrInvalidKey)
+	suite.mockKeyGateway.AssertNotCalled(suite.T(), "FindByValue", mock.Anything)
+}
+
+func (suite *KeyTestSuite) TestResolveKeyUseCase_Execute_WithGatewayError() {
+	suite.mockKeyGateway.On("FindByValue", mock.Anything).Return((*entity.Key)(nil), errors.New("connection refused"))
+	output, err := suite.resolveKeyUseCase.Execute(context.Background(), &ResolveKeyInput{Value: "guga@tennis.com"})
+
+	assert.Nil(suite.T(), output)
+	assert.EqualError(suite.T(), err, "connection refused")
+	assert.NotErrorIs(suite.T(), err, ErrKeyNotFound)
+}
+
+func TestKeyTestSuite(t *testing.T) {
+	suite.Run(t, new(KeyTestSuite))
+}
@@ -1,6 +1,9 @@
 package usecase
 
 import (
+	"context"
+	"time"
+
 	"github.com/josimarz/fc-eda-challenge/internal/entity"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,27 +12,136 @@ type MockCustomerGateway struct {
 	mock.Mock
 }
 
-func (m *MockCustomerGateway) Create(customer *entity.Customer) error {
+func (m *MockCustomerGateway) Create(ctx context.Context, customer *entity.Customer) error {
 	args := m.Called(customer)
 	return args.Error(0)
 }
 
-func (m *MockCustomerGateway) FindById(id string) (*entity.Customer, error) {
+func (m *MockCustomerGateway) FindById(ctx context.Context, id string) (*entity.Customer, error) {
 	args := m.Called(id)
 	return args.Get(0).(*entity.Customer), args.Error(1)
 }
 
-func (m *MockCustomerGateway) FindAll() ([]*entity.Customer, error) {
+func (m *MockCustomerGateway) FindAll(ctx context.Context) ([]*entity.Customer, error) {
 	args := m.Called()
 	return args.Get(0).([]*entity.Customer), args.Error(1)
 }
 
-func (m *MockCustomerGateway) Update(customer *entity.Customer) error {
+func (m *MockCustomerGateway) Update(ctx context.Context, customer *entity.Customer) error {
 	args := m.Called(customer)
 	return args.Error(0)
 }
 
-func (m *MockCustomerGateway) Delete(customer *entity.Customer) error {
+func (m *MockCustomerGateway) Delete(ctx context.Context, customer *entity.Customer) error {
 	args := m.Called(customer)
 	return args.Error(0)
 }
+
+type MockAccountGateway struct {
+	mock.Mock
+}
+
+func (m *MockAccountGateway) Create(ctx context.Context, account *entity.Account) error {
+	args := m.Called(account)
+	return args.Error(0)
+}
+
+func (m *MockAccountGateway) FindById(ctx context.Context, id string) (*entity.Account, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entity.Account), args.Error(1)
+}
+
+func (m *MockAccountGateway) FindByCustomer(ctx context.Context, customer *entity.Customer) ([]*entity.Account, error) {
+	args := m.Called(customer)
+	return args.Get(0).([]*entity.Account), args.Error(1)
+}
+
+func (m *MockAccountGateway) Update(ctx context.Context, account *entity.Account) error {
+	args := m.Called(account)
+	return args.Error(0)
+}
+
+type MockKeyGateway struct {
+	mock.Mock
+}
+
+func (m *MockKeyGateway) Create(ctx context.Context, key *entity.Key) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockKeyGateway) FindByValue(ctx context.Context, value string) (*entity.Key, error) {
+	args := m.Called(value)
+	return args.Get(0).(*entity.Key), args.Error(1)
+}
+
+func (m *MockKeyGateway) FindByCustomer(ctx context.Context, customer *entity.Customer) ([]*entity.Key, error) {
+	args := m.Called(customer)
+	return args.Get(0).([]*entity.Key), args.Error(1)
+}
+
+func (m *MockKeyGateway) Delete(ctx context.Context, key *entity.Key) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+type MockTransactionGateway struct {
+	mock.Mock
+}
+
+func (m *MockTransactionGateway) Create(ctx context.Context, transaction *entity.Transaction) error {
+	args := m.Called(transaction)
+	return args.Error(0)
+}
+
+type MockPaymentRequestGateway struct {
+	mock.Mock
+}
+
+func (m *MockPaymentRequestGateway) Create(ctx context.Context, request *entity.PaymentRequest) error {
+	args := m.Called(request)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRequestGateway) FindById(ctx context.Context, id string) (*entity.PaymentRequest, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entity.PaymentRequest), args.Error(1)
+}
+
+func (m *MockPaymentRequestGateway) FindByPayerCustomer(ctx context.Context, customer *entity.Customer, status entity.PaymentRequestStatus) ([]*entity.PaymentRequest, error) {
+	args := m.Called(customer, status)
+	return args.Get(0).([]*entity.PaymentRequest), args.Error(1)
+}
+
+func (m *MockPaymentRequestGateway) FindExpired(ctx context.Context, now time.Time) ([]*entity.PaymentRequest, error) {
+	args := m.Called(now)
+	return args.Get(0).([]*entity.PaymentRequest), args.Error(1)
+}
+
+func (m *MockPaymentRequestGateway) Update(ctx context.Context, request *entity.PaymentRequest) error {
+	args := m.Called(request)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRequestGateway) Reopen(ctx context.Context, request *entity.PaymentRequest) error {
+	args := m.Called(request)
+	return args.Error(0)
+}
+
+type MockProcessedMessageGateway struct {
+	mock.Mock
+}
+
+func (m *MockProcessedMessageGateway) MarkProcessed(ctx context.Context, id string) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockTransactor struct {
+	mock.Mock
+}
+
+func (m *MockTransactor) Transact(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.Called()
+	return fn(ctx)
+}
@@ -0,0 +1,299 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/josimarz/fc-eda-challenge/internal/entity"
+	eventhandling "github.com/josimarz/fc-eda-challenge/internal/event_handling"
+	"github.com/josimarz/fc-eda-challenge/internal/gateway"
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+)
+
+const defaultPaymentRequestTTL = 24 * time.Hour
+
+type PaymentRequestOutput struct {
+	Id          string    `json:"id"`
+	RequesterId string    `json:"requesterId"`
+	PayerId     string    `json:"payerId"`
+	Amount      float64   `json:"amount"`
+	Memo        string    `json:"memo"`
+	Status      string    `json:"status"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type CreatePaymentRequestInput struct {
+	CustomerId string
+	AccountId  string    `json:"accountId"`
+	Payer      string    `json:"payer"`
+	PayerKey   string    `json:"payerKey"`
+	Amount     float64   `json:"amount"`
+	Memo       string    `json:"memo"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+type CreatePaymentRequestUseCase struct {
+	paymentRequestGateway gateway.PaymentRequestGateway
+	accountGateway        gateway.AccountGateway
+	keyGateway            gateway.KeyGateway
+	eventDispatcher       events.Dispatcher
+}
+
+func NewCreatePaymentRequestUseCase(
+	paymentRequestGateway gateway.PaymentRequestGateway,
+	accountGateway gateway.AccountGateway,
+	keyGateway gateway.KeyGateway,
+	eventDispatcher events.Dispatcher,
+) *CreatePaymentRequestUseCase {
+	return &CreatePaymentRequestUseCase{paymentRequestGateway, accountGateway, keyGateway, eventDispatcher}
+}
+
+func (uc *CreatePaymentRequestUseCase) Execute(ctx context.Context, input *CreatePaymentRequestInput) (*PaymentRequestOutput, error) {
+	requester, err := uc.accountGateway.FindById(ctx, input.AccountId)
+	if err != nil {
+		return nil, err
+	}
+	if requester.Customer == nil || requester.Customer.Id != input.CustomerId {
+		return nil, errors.New("account does not belong to customer")
+	}
+	payer, err := uc.findPayer(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := input.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(defaultPaymentRequestTTL)
+	}
+	request, err := entity.NewPaymentRequest(requester, payer, input.Amount, input.Memo, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.paymentRequestGateway.Create(ctx, request); err != nil {
+		return nil, err
+	}
+	output := newPaymentRequestOutput(request)
+	event := eventhandling.NewPaymentRequestCreatedEvent(newPaymentRequestPayload(request))
+	if err := uc.eventDispatcher.Dispatch(ctx, event); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+func (uc *CreatePaymentRequestUseCase) findPayer(ctx context.Context, input *CreatePaymentRequestInput) (*entity.Account, error) {
+	if input.Payer != "" && input.PayerKey != "" {
+		return nil, errors.New("either payer or payerKey must be informed, not both")
+	}
+	if input.PayerKey == "" {
+		return uc.accountGateway.FindById(ctx, input.Payer)
+	}
+	_, value, err := entity.ParseKeyValue(input.PayerKey)
+	if err != nil {
+		return nil, err
+	}
+	key, err := uc.keyGateway.FindByValue(ctx, value)
+	if errors.Is(err, gateway.ErrNotFound) {
+		return nil, errors.New("key not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return key.Account, nil
+}
+
+type ListPaymentRequestsInput struct {
+	CustomerId string
+	Status     string
+}
+
+type ListPaymentRequestsOutput struct {
+	PaymentRequests []*PaymentRequestOutput `json:"paymentRequests"`
+}
+
+type ListPaymentRequestsUseCase struct {
+	paymentRequestGateway gateway.PaymentRequestGateway
+	customerGateway       gateway.CustomerGateway
+}
+
+func NewListPaymentRequestsUseCase(paymentRequestGateway gateway.PaymentRequestGateway, customerGateway gateway.CustomerGateway) *ListPaymentRequestsUseCase {
+	return &ListPaymentRequestsUseCase{paymentRequestGateway, customerGateway}
+}
+
+func (uc *ListPaymentRequestsUseCase) Execute(ctx context.Context, input *ListPaymentRequestsInput) (*ListPaymentRequestsOutput, error) {
+	customer, err := uc.customerGateway.FindById(ctx, input.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := uc.paymentRequestGateway.FindByPayerCustomer(ctx, customer, entity.PaymentRequestStatus(input.Status))
+	if err != nil {
+		return nil, err
+	}
+	output := &ListPaymentRequestsOutput{}
+	for _, request := range requests {
+		output.PaymentRequests = append(output.PaymentRequests, newPaymentRequestOutput(request))
+	}
+	return output, nil
+}
+
+type AnswerPaymentRequestInput struct {
+	CustomerId string
+	Id         string
+}
+
+type AcceptPaymentRequestUseCase struct {
+	paymentRequestGateway gateway.PaymentRequestGateway
+	eventDispatcher       events.Dispatcher
+}
+
+func NewAcceptPaymentRequestUseCase(paymentRequestGateway gateway.PaymentRequestGateway, eventDispatcher events.Dispatcher) *AcceptPaymentRequestUseCase {
+	return &AcceptPaymentRequestUseCase{paymentRequestGateway, eventDispatcher}
+}
+
+func (uc *AcceptPaymentRequestUseCase) Execute(ctx context.Context, input *AnswerPaymentRequestInput) (*PaymentRequestOutput, error) {
+	request, err := findPayerPaymentRequest(ctx, uc.paymentRequestGateway, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := request.Accept(time.Now()); err != nil {
+		return nil, err
+	}
+	if err := uc.paymentRequestGateway.Update(ctx, request); err != nil {
+		return nil, err
+	}
+	transfer := eventhandling.NewTransactionCreatedEvent(eventhandling.TransactionCreatedPayload{
+		From:   request.Payer.Id,
+		To:     request.Requester.Id,
+		Amount: request.Amount,
+	})
+	if err := uc.eventDispatcher.Dispatch(ctx, transfer); err != nil {
+		return nil, uc.reopen(ctx, request, err)
+	}
+	output := newPaymentRequestOutput(request)
+	event := eventhandling.NewPaymentRequestAcceptedEvent(newPaymentRequestPayload(request))
+	if err := uc.eventDispatcher.Dispatch(ctx, event); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// reopen puts the request back to pending so the payer can accept it again
+// after the transfer event failed to go out.
+func (uc *AcceptPaymentRequestUseCase) reopen(ctx context.Context, request *entity.PaymentRequest, cause error) error {
+	if err := request.Reopen(time.Now()); err != nil {
+		return errors.Join(cause, err)
+	}
+	if err := uc.paymentRequestGateway.Reopen(ctx, request); err != nil {
+		return errors.Join(cause, err)
+	}
+	return cause
+}
+
+type DeclinePaymentRequestUseCase struct {
+	paymentRequestGateway gateway.PaymentRequestGateway
+	eventDispatcher       events.Dispatcher
+}
+
+func NewDeclinePaymentRequestUseCase(paymentRequestGateway gateway.PaymentRequestGateway, eventDispatcher events.Dispatcher) *DeclinePaymentRequestUseCase {
+	return &DeclinePaymentRequestUseCase{paymentRequestGateway, eventDispatcher}
+}
+
+func (uc *DeclinePaymentRequestUseCase) Execute(ctx context.Context, input *AnswerPaymentRequestInput) (*PaymentRequestOutput, error) {
+	request, err := findPayerPaymentRequest(ctx, uc.paymentRequestGateway, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := request.Decline(time.Now()); err != nil {
+		return nil, err
+	}
+	if err := uc.paymentRequestGateway.Update(ctx, request); err != nil {
+		return nil, err
+	}
+	output := newPaymentRequestOutput(request)
+	event := eventhandling.NewPaymentRequestDeclinedEvent(newPaymentRequestPayload(request))
+	if err := uc.eventDispatcher.Dispatch(ctx, event); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+type ExpirePaymentRequestsInput struct {
+	Now time.Time
+}
+
+type ExpirePaymentRequestsOutput struct {
+	Expired int `json:"expired"`
+}
+
+type ExpirePaymentRequestsUseCase struct {
+	paymentRequestGateway gateway.PaymentRequestGateway
+	eventDispatcher       events.Dispatcher
+}
+
+func NewExpirePaymentRequestsUseCase(paymentRequestGateway gateway.PaymentRequestGateway, eventDispatcher events.Dispatcher) *ExpirePaymentRequestsUseCase {
+	return &ExpirePaymentRequestsUseCase{paymentRequestGateway, eventDispatcher}
+}
+
+func (uc *ExpirePaymentRequestsUseCase) Execute(ctx context.Context, input *ExpirePaymentRequestsInput) (*ExpirePaymentRequestsOutput, error) {
+	requests, err := uc.paymentRequestGateway.FindExpired(ctx, input.Now)
+	if err != nil {
+		return nil, err
+	}
+	output := &ExpirePaymentRequestsOutput{}
+	for _, request := range requests {
+		if err := request.Expire(input.Now); err != nil {
+			continue
+		}
+		if err := uc.paymentRequestGateway.Update(ctx, request); errors.Is(err, gateway.ErrConflict) {
+			continue
+		} else if err != nil {
+			return output, err
+		}
+		event := eventhandling.NewPaymentRequestExpiredEvent(newPaymentRequestPayload(request))
+		if err := uc.eventDispatcher.Dispatch(ctx, event); err != nil {
+			return output, err
+		}
+		output.Expired++
+	}
+	return output, nil
+}
+
+func findPayerPaymentRequest(ctx context.Context, paymentRequestGateway gateway.PaymentRequestGateway, input *AnswerPaymentRequestInput) (*entity.PaymentRequest, error) {
+	request, err := paymentRequestGateway.FindById(ctx, input.Id)
+	if err != nil {
+		return nil, err
+	}
+	if request.Payer.Customer == nil || request.Payer.Customer.Id != input.CustomerId {
+		return nil, errors.New("payment request does not belong to customer")
+	}
+	return request, nil
+}
+
+func newPaymentRequestOutput(request *entity.PaymentRequest) *PaymentRequestOutput {
+	return &PaymentRequestOutput{
+		Id:          request.Id,
+		RequesterId: request.Requester.Id,
+		PayerId:     request.Payer.Id,
+		Amount:      request.Amount,
+		Memo:        request.Memo,
+		Status:      string(request.Status),
+		ExpiresAt:   request.ExpiresAt,
+		CreatedAt:   request.CreatedAt,
+		UpdatedAt:   request.UpdatedAt,
+	}
+}
+
+func newPaymentRequestPayload(request *entity.PaymentRequest) eventhandling.PaymentRequestPayload {
+	return eventhandling.PaymentRequestPayload{
+		Id:          request.Id,
+		RequesterId: request.Requester.Id,
+		PayerId:     request.Payer.Id,
+		Amount:      request.Amount,
+		Memo:        request.Memo,
+		Status:      string(request.Status),
+		ExpiresAt:   request.ExpiresAt,
+		CreatedAt:   request.CreatedAt,
+		UpdatedAt:   request.UpdatedAt,
+	}
+}
@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/josimarz/fc-eda-challenge/internal/entity"
+	eventhandling "github.com/josimarz/fc-eda-challenge/internal/event_handling"
+	"github.com/josimarz/fc-eda-challenge/internal/gateway"
+	"github.com/josimarz/fc-eda-challenge/pkg/events/eventstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PaymentRequestTestSuite struct {
+	suite.Suite
+	requester                    *entity.Account
+	payer                        *entity.Account
+	dispatcher                   *eventstest.Dispatcher
+	mockPaymentRequestGateway    *MockPaymentRequestGateway
+	mockAccountGateway           *MockAccountGateway
+	mockKeyGateway               *MockKeyGateway
+	createPaymentRequestUseCase  *CreatePaymentRequestUseCase
+	acceptPaymentRequestUseCase  *AcceptPaymentRequestUseCase
+	declinePaymentRequestUseCase *DeclinePaymentRequestUseCase
+	expirePaymentRequestsUseCase *ExpirePaymentRequestsUseCase
+}
+
+func (suite *PaymentRequestTestSuite) SetupTest() {
+	customer, _ := entity.NewCustomer("Ana Ivanovic", "ivanovic@wta.com")
+	suite.requester = entity.NewAccount(customer)
+	customer, _ = entity.NewCustomer("Maria Sharapova", "sharapova@wta.com")
+	suite.payer = entity.NewAccount(customer)
+
+	suite.dispatcher = eventstest.NewDispatcher(suite.T())
+	suite.mockPaymentRequestGateway = &MockPaymentRequestGateway{}
+	suite.mockAccountGateway = &MockAccountGateway{}
+	suite.mockKeyGateway = &MockKeyGateway{}
+	suite.createPaymentRequestUseCase = NewCreatePaymentRequestUseCase(suite.mockPaymentRequestGateway, suite.mockAccountGateway, suite.mockKeyGateway, suite.dispatcher)
+	suite.acceptPaymentRequestUseCase = NewAcceptPaymentRequestUseCase(suite.mockPaymentRequestGateway, suite.dispatcher)
+	suite.declinePaymentRequestUseCase = NewDeclinePaymentRequestUseCase(suite.mockPaymentRequestGateway, suite.dispatcher)
+	suite.expirePaymentRequestsUseCase = NewExpirePaymentRequestsUseCase(suite.mockPaymentRequestGateway, suite.dispatcher)
+}
+
+func (suite *PaymentRequestTestSuite) TestCreatePaymentRequestUseCase_Execute_WithPayerKey() {
+	key, _ := entity.NewKey(entity.EmailKey, suite.payer.Customer.Email, suite.payer)
+	suite.mockAccountGateway.On("FindById", suite.requester.Id).Return(suite.requester, nil)
+	suite.mockKeyGateway.On("FindByValue", key.Value).Return(key, nil)
+	suite.mockPaymentRequestGateway.On("Create", mock.Anything).Return(nil)
+	input := &CreatePaymentRequestInput{
+		CustomerId: suite.requester.Customer.Id,
+		AccountId:  suite.requester.Id,
+		PayerKey:   key.Value,
+		Amount:     80.0,
+		Memo:       "tennis balls",
+	}
+	output, err := suite.createPaymentRequestUseCase.Execute(context.Background(), input)
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), suite.payer.Id, output.PayerId)
+	assert.Equal(suite.T(), suite.requester.Id, output.RequesterId)
+	assert.Equal(suite.T(), "pending", output.Status)
+	assert.WithinDuration(suite.T(), time.Now().Add(defaultPaymentRequestTTL), output.ExpiresAt, time.Minute)
+	assert.Equal(suite.T(), []string{"payment_request.created"}, suite.dispatcher.Names())
+	event := suite.dispatcher.Events()[0].(*eventhandling.PaymentRequestEvent)
+	assert.Equal(suite.T(), output.Id, event.Payload().Id)
+}
+
+func (suite *PaymentRequestTestSuite) TestCreatePaymentRequestUseCase_Execute_WithAccountOfAnotherCustomer() {
+	suite.mockAccountGateway.On("FindById", suite.requester.Id).Return(suite.requester, nil)
+	input := &CreatePaymentRequestInput{
+		CustomerId: suite.payer.Customer.Id,
+		AccountId:  suite.requester.Id,
+		Payer:      suite.payer.Id,
+		Amount:     80.0,
+	}
+	output, err := suite.createPaymentRequestUseCase.Execute(context.Background(), input)
+
+	assert.Nil(suite.T(), output)
+	assert.EqualError(suite.T(), err, "account does not belong to customer")
+	suite.mockPaymentRequestGateway.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *PaymentRequestTestSuite) TestAcceptPaymentRequestUseCase_Execute() {
+	request, _ := entity.NewPaymentRequest(suite.requester, suite.payer, 80.0, "", time.Now().Add(time.Hour))
+	suite.mockPaymentRequestGateway.On("FindById", request.Id).Return(request, nil)
+	suite.mockPaymentRequestGateway.On("Update", request).Return(nil)
+	input := &AnswerPaymentRequestInput{CustomerId: suite.payer.Customer.Id, Id: request.Id}
+	output, err := suite.acceptPaymentRequestUseCase.Execute(context.Background(), input)
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "accepted", output.Status)
+	suite.dispatcher.AssertOrder("transaction.created", "payment_request.accepted")
+	suite.dispatcher.AssertDispatched("transaction.created", eventstest.PayloadEquals(eventhandling.TransactionCreatedPayload{
+		From:   suite.payer.Id,
+		To:     suite.requester.Id,
+		Amount: 80.0,
+	}))
+}
+
+func (suite *PaymentRequestTestSuite) TestAcceptPaymentRequestUseCase_Execute_ByRequester() {
+	request, _ := entity.NewPaymentRequest(suite.requester, suite.payer, 80.0, "", time.Now().Add(time.Hour))
+	suite.mockPaymentRequestGateway.On("FindById", request.Id).Return(request, nil)
+	input := &AnswerPaymentRequestInput{CustomerId: suite.requester.Customer.Id, Id: request.Id}
+	output, err := suite.acceptPaymentRequestUseCase.Execute(context.Background(), input)
+
+	assert.Nil(suite.T(), output)
+	assert.EqualError(suite.T(), err, "payment request does not belong to customer")
+	assert.Empty(suite.T(), suite.dispatcher.Events())
+}
+
+func (suite *PaymentRequestTestSuite) TestDeclinePaymentRequestUseCase_Execute() {
+	request, _ := entity.NewPaymentRequest(suite.requester, suite.payer, 80.0, "", time.Now().Add(time.Hour))
+	suite.mockPaymentRequestGateway.On("FindById", request.Id).Return(request, nil)
+	suite.mockPaymentRequestGateway.On("Update", request).Return(nil)
+	input := &AnswerPaymentRequestInput{CustomerId: suite.payer.Customer.Id, Id: request.Id}
+	output, err := suite.declinePaymentRequestUseCase.Execute(context.Background(), input)
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "declined", output.Status)
+	assert.Equal(suite.T(), []string{"payment_request.declined"}, suite.dispatcher.Names())
+}
+
+func (suite *PaymentRequestTestSuite) TestExpirePaymentRequestsUseCase_Execute() {
+	request, _ := entity.NewPaymentRequest(suite.requester, suite.payer, 80.0, "", time.Now().Add(time.Hour))
+	now := time.Now().Add(2 * time.Hour)
+	suite.mockPaymentRequestGateway.On("FindExpired", now).Return([]*entity.PaymentRequest{request}, nil)
+	suite.mockPaymentRequestGateway.On("Update", request).Return(nil)
+	output, err := suite.expirePaymentRequestsUseCase.Execute(context.Background(), &ExpirePaymentRequestsInput{Now: now})
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 1, output.Expired)
+	assert.Equal(suite.T(), entity.PaymentRequestExpired, request.Status)
+	assert.Equal(suite.T(), []string{"payment_request.expired"}, suite.dispatcher.Names())
+}
+
+func (suite *PaymentRequestTestSuite) TestExpirePaymentRequestsUseCase_Execute_WithAnsweredRequest() {
+	request, _ := entity.NewPaymentRequest(suite.requester, suite.payer, 80.0, "", time.Now().Add(time.Hour))
+	now := time.Now().Add(2 * time.Hour)
+	suite.mockPaymentRequestGateway.On("FindExpired", now).Return([]*entity.PaymentRequest{request}, nil)
+	suite.mockPaymentRequestGateway.On("Update", request).Return(gateway.ErrConflict)
+	output, err := suite.expirePaymentRequestsUseCase.Execute(context.Background(), &ExpirePaymentRequestsInput{Now: now})
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 0, output.Expired)
+	assert.Empty(suite.T(), suite.dispatcher.Events())
+}
+
+func (suite *PaymentRequestTestSuite) TestAcceptPaymentRequestUseCase_Execute_WithDispatchError() {
+	request, _ := entity.NewPaymentRequest(suite.requester, suite.payer, 80.0, "", time.Now().Add(time.Hour))
+	suite.mockPaymentRequestGateway.On("FindById", request.Id).Return(request, nil)
+	suite.mockPaymentRequestGateway.On("Update", request).Return(nil)
+	suite.mockPaymentRequestGateway.On("Reopen", request).Return(nil)
+	suite.dispatcher.FailWith(errors.New("broker unavailable"))
+	input := &AnswerPaymentRequestInput{CustomerId: suite.payer.Customer.Id, Id: request.Id}
+	output, err := suite.acceptPaymentRequestUseCase.Execute(context.Background(), input)
+
+	assert.Nil(suite.T(), output)
+	assert.ErrorContains(suite.T(), err, "broker unavailable")
+	assert.Equal(suite.T(), entity.PaymentRequestPending, request.Status)
+	suite.mockPaymentRequestGateway.AssertCalled(suite.T(), "Reopen", request)
+}
+
+func (suite *PaymentRequestTestSuite) TestAcceptPaymentRequestUseCase_Execute_WithDispatchAndReopenError() {
+	request, _ := entity.NewPaymentRequest(suite.requester, suite.payer, 80.0, "", time.Now().Add(time.Hour))
+	suite.mockPaymentRequestGateway.On("FindById", request.Id).Return(request, nil)
+	suite.mockPaymentRequestGateway.On("Update", request).Return(nil)
+	suite.mockPaymentRequestGateway.On("Reopen", request).Return(gateway.ErrConflict)
+	suite.dispatcher.FailWith(errors.New("broker unavailable"))
+	input := &AnswerPaymentRequestInput{CustomerId: suite.payer.Customer.Id, Id: request.Id}
+	output, err := suite.acceptPaymentRequestUseCase.Execute(context.Background(), input)
+
+	assert.Nil(suite.T(), output)
+	assert.ErrorContains(suite.T(), err, "broker unavailable")
+	assert.ErrorIs(suite.T(), err, gateway.ErrConflict)
+}
+
+func (suite *PaymentRequestTestSuite) TestAcceptPaymentRequestUseCase_Execute_WithConflict() {
+	request, _ := entity.NewPaymentRequest(suite.requester, suite.payer, 80.0, "", time.Now().Add(time.Hour))
+	suite.mockPaymentRequestGateway.On("FindById", request.Id).Return(request, nil)
+	suite.mockPaymentRequestGateway.On("Update", request).Return(gateway.ErrConflict)
+	input := &AnswerPaymentRequestInput{CustomerId: suite.payer.Customer.Id, Id: request.Id}
+	output, err := suite.acceptPaymentRequestUseCase.Execute(context.Background(), input)
+
+	assert.Nil(suite.T(), output)
+	assert.ErrorIs(suite.T(), err, gateway.ErrConflict)
+	assert.Empty(suite.T(), suite.dispatcher.Events())
+}
+
+func TestPaymentRequestTestSuite(t *testing.T) {
+	suite.Run(t, new(PaymentRequestTestSuite))
+}
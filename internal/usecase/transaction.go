@@ -1,6 +1,8 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/josimarz/fc-eda-challenge/internal/entity"
 	eventhandling "github.com/josimarz/fc-eda-challenge/internal/event_handling"
 	"github.com/josimarz/fc-eda-challenge/internal/gateway"
@@ -8,8 +10,10 @@ import (
 )
 
 type CreateTransactionInput struct {
+	Id     string  `json:"-"`
 	From   string  `json:"from"`
 	To     string  `json:"to"`
+	ToKey  string  `json:"toKey,omitempty"`
 	Amount float64 `json:"amount"`
 }
 
@@ -22,23 +26,23 @@ type CreateTransactionOutput struct {
 type CreateTransactionUseCase struct {
 	transactionGateway gateway.TransactionGateway
 	accountGateway     gateway.AccountGateway
-	eventDispatcher    *events.EventDispatcher
+	eventDispatcher    events.Dispatcher
 }
 
 func NewCreateTransactionUseCase(
 	transactionGateway gateway.TransactionGateway,
 	accountGateway gateway.AccountGateway,
-	eventDispatcher *events.EventDispatcher,
+	eventDispatcher events.Dispatcher,
 ) *CreateTransactionUseCase {
 	return &CreateTransactionUseCase{transactionGateway, accountGateway, eventDispatcher}
 }
 
-func (uc *CreateTransactionUseCase) Execute(input *CreateTransactionInput) (*CreateTransactionOutput, error) {
-	from, err := uc.accountGateway.FindById(input.From)
+func (uc *CreateTransactionUseCase) Execute(ctx context.Context, input *CreateTransactionInput) (*CreateTransactionOutput, error) {
+	from, err := uc.accountGateway.FindById(ctx, input.From)
 	if err != nil {
 		return nil, err
 	}
-	to, err := uc.accountGateway.FindById(input.To)
+	to, err := uc.accountGateway.FindById(ctx, input.To)
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +50,10 @@ func (uc *CreateTransactionUseCase) Execute(input *CreateTransactionInput) (*Cre
 	if err != nil {
 		return nil, err
 	}
-	if err := uc.transactionGateway.Create(transaction); err != nil {
+	if input.Id != "" {
+		transaction.Id = input.Id
+	}
+	if err := uc.transactionGateway.Create(ctx, transaction); err != nil {
 		return nil, err
 	}
 	output := &CreateTransactionOutput{
@@ -64,8 +71,13 @@ func (uc *CreateTransactionUseCase) Execute(input *CreateTransactionInput) (*Cre
 		},
 		Amount: transaction.Amount,
 	}
-	event := eventhandling.NewBalancesUpdatedEvent()
-	event.SetPayload(output)
-	uc.eventDispatcher.Dispatch(event)
+	event := eventhandling.NewBalancesUpdatedEvent(eventhandling.BalancesUpdatedPayload{
+		From:   eventhandling.AccountBalance(output.From),
+		To:     eventhandling.AccountBalance(output.To),
+		Amount: output.Amount,
+	})
+	if err := uc.eventDispatcher.Dispatch(ctx, event); err != nil {
+		return nil, err
+	}
 	return output, nil
 }
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/josimarz/fc-eda-challenge/internal/entity"
+	eventhandling "github.com/josimarz/fc-eda-challenge/internal/event_handling"
+	"github.com/josimarz/fc-eda-challenge/pkg/events/eventstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TransactionTestSuite struct {
+	suite.Suite
+	from                     *entity.Account
+	to                       *entity.Account
+	dispatcher               *eventstest.Dispatcher
+	mockTransactionGateway   *MockTransactionGateway
+	mockAccountGateway       *MockAccountGateway
+	createTransactionUseCase *CreateTransactionUseCase
+}
+
+func (suite *TransactionTestSuite) SetupTest() {
+	customer, _ := entity.NewCustomer("Gustavo Kuerten", "guga@tennis.com")
+	suite.from = entity.NewAccount(customer)
+	suite.from.Deposit(100.0)
+	customer, _ = entity.NewCustomer("Ana Ivanovic", "ivanovic@wta.com")
+	suite.to = entity.NewAccount(customer)
+	suite.dispatcher = eventstest.NewDispatcher(suite.T())
+	suite.mockTransactionGateway = &MockTransactionGateway{}
+	suite.mockAccountGateway = &MockAccountGateway{}
+	suite.createTransactionUseCase = NewCreateTransactionUseCase(suite.mockTransactionGateway, suite.mockAccountGateway, suite.dispatcher)
+}
+
+func (suite *TransactionTestSuite) TestCreateTransactionUseCase_Execute() {
+	suite.mockAccountGateway.On("FindById", suite.from.Id).Return(suite.from, nil)
+	suite.mockAccountGateway.On("FindById", suite.to.Id).Return(suite.to, nil)
+	suite.mockTransactionGateway.On("Create", mock.Anything).Return(nil)
+	input := &CreateTransactionInput{From: suite.from.Id, To: suite.to.Id, Amount: 30.0}
+	output, err := suite.createTransactionUseCase.Execute(context.Background(), input)
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 70.0, output.From.Balance)
+	assert.Equal(suite.T(), 30.0, output.To.Balance)
+	suite.dispatcher.AssertCount(eventhandling.BalancesUpdated, 1)
+	suite.dispatcher.AssertDispatched(eventhandling.BalancesUpdated, eventstest.Payload(func(payload eventhandling.BalancesUpdatedPayload) bool {
+		return payload.From.Id == suite.from.Id && payload.To.Id == suite.to.Id && payload.Amount == 30.0
+	}))
+}
+
+func (suite *TransactionTestSuite) TestCreateTransactionUseCase_Execute_WithInsufficientFunds() {
+	suite.mockAccountGateway.On("FindById", suite.from.Id).Return(suite.from, nil)
+	suite.mockAccountGateway.On("FindById", suite.to.Id).Return(suite.to, nil)
+	input := &CreateTransactionInput{From: suite.from.Id, To: suite.to.Id, Amount: 300.0}
+	output, err := suite.createTransactionUseCase.Execute(context.Background(), input)
+
+	assert.Nil(suite.T(), output)
+	assert.EqualError(suite.T(), err, "unable to execute transaction: insufficient funds")
+	suite.mockTransactionGateway.AssertNotCalled(suite.T(), "Create", mock.Anything)
+	suite.dispatcher.AssertNotDispatched(eventhandling.BalancesUpdated)
+}
+
+func (suite *TransactionTestSuite) TestCreateTransactionUseCase_Execute_WithDispatchError() {
+	suite.mockAccountGateway.On("FindById", suite.from.Id).Return(suite.from, nil)
+	suite.mockAccountGateway.On("FindById", suite.to.Id).Return(suite.to, nil)
+	suite.mockTransactionGateway.On("Create", mock.Anything).Return(nil)
+	suite.dispatcher.FailWith(errors.New("broker unavailable"))
+	input := &CreateTransactionInput{From: suite.from.Id, To: suite.to.Id, Amount: 30.0}
+	output, err := suite.createTransactionUseCase.Execute(context.Background(), input)
+
+	assert.Nil(suite.T(), output)
+	assert.EqualError(suite.T(), err, "broker unavailable")
+}
+
+func TestTransactionTestSuite(t *testing.T) {
+	suite.Run(t, new(TransactionTestSuite))
+}
@@ -0,0 +1,85 @@
+package codec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/linkedin/goavro/v2"
+)
+
+type Avro struct {
+	codecs map[string]*goavro.Codec
+}
+
+func NewAvro(schemas map[string]string) (*Avro, error) {
+	c := &Avro{codecs: make(map[string]*goavro.Codec)}
+	for key, schema := range schemas {
+		codec, err := goavro.NewCodec(schema)
+		if err != nil {
+			return nil, fmt.Errorf("avro schema %s: %w", key, err)
+		}
+		c.codecs[key] = codec
+	}
+	return c, nil
+}
+
+func LoadAvro(dir string) (*Avro, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.avsc"))
+	if err != nil {
+		return nil, err
+	}
+	schemas := make(map[string]string)
+	for _, file := range files {
+		schema, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		schemas[strings.TrimSuffix(filepath.Base(file), ".avsc")] = string(schema)
+	}
+	return NewAvro(schemas)
+}
+
+func (c *Avro) ContentType() string {
+	return "application/avro"
+}
+
+func (c *Avro) Encode(envelope *events.Envelope) ([]byte, error) {
+	codec, err := c.codec(envelope)
+	if err != nil {
+		return nil, err
+	}
+	native, _, err := codec.NativeFromTextual(envelope.Data)
+	if err != nil {
+		return nil, err
+	}
+	return codec.BinaryFromNative(nil, native)
+}
+
+func (c *Avro) Decode(data []byte, envelope *events.Envelope) error {
+	codec, err := c.codec(envelope)
+	if err != nil {
+		return err
+	}
+	native, _, err := codec.NativeFromBinary(data)
+	if err != nil {
+		return err
+	}
+	value, err := codec.TextualFromNative(nil, native)
+	if err != nil {
+		return err
+	}
+	envelope.Data = value
+	return nil
+}
+
+func (c *Avro) codec(envelope *events.Envelope) (*goavro.Codec, error) {
+	key := SchemaKey(envelope.Type, envelope.SchemaVersion)
+	codec, ok := c.codecs[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSchema, key)
+	}
+	return codec, nil
+}
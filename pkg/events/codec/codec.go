@@ -0,0 +1,63 @@
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+)
+
+var (
+	ErrUnknownContentType = errors.New("unknown content type")
+	ErrUnknownSchema      = errors.New("unknown event schema")
+)
+
+type Codec interface {
+	ContentType() string
+	Encode(envelope *events.Envelope) ([]byte, error)
+	Decode(data []byte, envelope *events.Envelope) error
+}
+
+type Registry struct {
+	codecs map[string]Codec
+}
+
+func NewRegistry(codecs ...Codec) *Registry {
+	registry := &Registry{codecs: make(map[string]Codec)}
+	for _, codec := range codecs {
+		registry.codecs[codec.ContentType()] = codec
+	}
+	return registry
+}
+
+func (r *Registry) Get(contentType string) (Codec, error) {
+	codec, ok := r.codecs[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownContentType, contentType)
+	}
+	return codec, nil
+}
+
+func (r *Registry) ForTopics(spec string) (map[string]Codec, error) {
+	codecs := make(map[string]Codec)
+	for _, entry := range strings.Split(spec, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		topic, name, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid topic codec %q", entry)
+		}
+		codec, err := r.Get("application/" + strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		codecs[strings.TrimSpace(topic)] = codec
+	}
+	return codecs, nil
+}
+
+func SchemaKey(eventType string, version int) string {
+	return fmt.Sprintf("%s.v%d", eventType, version)
+}
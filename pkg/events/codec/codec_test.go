@@ -0,0 +1,85 @@
+package codec
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const testSchema = `{
+	"type": "record",
+	"name": "Test",
+	"fields": [
+		{ "name": "id", "type": "string" },
+		{ "name": "amount", "type": "double" }
+	]
+}`
+
+func newTestEnvelope(data string) *events.Envelope {
+	return &events.Envelope{Type: "test", SchemaVersion: 1, Data: json.RawMessage(data)}
+}
+
+func roundTrip(t *testing.T, c Codec, data string) *events.Envelope {
+	encoded, err := c.Encode(newTestEnvelope(data))
+	assert.Nil(t, err)
+	decoded := newTestEnvelope("")
+	assert.Nil(t, c.Decode(encoded, decoded))
+	return decoded
+}
+
+func TestJSON(t *testing.T) {
+	c := NewJSON()
+	assert.Equal(t, "application/json", c.ContentType())
+	assert.JSONEq(t, `{"id":"1","amount":10}`, string(roundTrip(t, c, `{"id":"1","amount":10}`).Data))
+	assert.NotNil(t, c.Decode([]byte("not json"), newTestEnvelope("")))
+}
+
+func TestAvro(t *testing.T) {
+	c, err := NewAvro(map[string]string{"test.v1": testSchema})
+	assert.Nil(t, err)
+	assert.Equal(t, "application/avro", c.ContentType())
+	assert.JSONEq(t, `{"id":"1","amount":10}`, string(roundTrip(t, c, `{"id":"1","amount":10}`).Data))
+
+	_, err = c.Encode(&events.Envelope{Type: "test", SchemaVersion: 2})
+	assert.ErrorIs(t, err, ErrUnknownSchema)
+	_, err = c.Encode(newTestEnvelope(`{"id":"1"}`))
+	assert.NotNil(t, err)
+
+	_, err = NewAvro(map[string]string{"test.v1": `{"type":"unknown"}`})
+	assert.NotNil(t, err)
+}
+
+func TestProtobuf(t *testing.T) {
+	c := NewProtobuf(map[string]proto.Message{"test.v1": &structpb.Struct{}})
+	assert.Equal(t, "application/protobuf", c.ContentType())
+	assert.JSONEq(t, `{"id":"1","amount":10}`, string(roundTrip(t, c, `{"id":"1","amount":10}`).Data))
+
+	_, err := c.Encode(&events.Envelope{Type: "other", SchemaVersion: 1})
+	assert.ErrorIs(t, err, ErrUnknownSchema)
+}
+
+func TestRegistry(t *testing.T) {
+	avro, _ := NewAvro(nil)
+	registry := NewRegistry(NewJSON(), avro)
+
+	c, err := registry.Get("application/avro")
+	assert.Nil(t, err)
+	assert.Equal(t, avro, c)
+	_, err = registry.Get("application/xml")
+	assert.ErrorIs(t, err, ErrUnknownContentType)
+
+	codecs, err := registry.ForTopics("balances=avro, transactions=json")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]Codec{"balances": avro, "transactions": registry.codecs["application/json"]}, codecs)
+	codecs, err = registry.ForTopics("")
+	assert.Nil(t, err)
+	assert.Empty(t, codecs)
+	_, err = registry.ForTopics("balances=xml")
+	assert.ErrorIs(t, err, ErrUnknownContentType)
+	_, err = registry.ForTopics("balances")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,30 @@
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+)
+
+type JSON struct{}
+
+func NewJSON() *JSON {
+	return &JSON{}
+}
+
+func (c *JSON) ContentType() string {
+	return "application/json"
+}
+
+func (c *JSON) Encode(envelope *events.Envelope) ([]byte, error) {
+	return envelope.Data, nil
+}
+
+func (c *JSON) Decode(data []byte, envelope *events.Envelope) error {
+	if !json.Valid(data) {
+		return errors.New("invalid json payload")
+	}
+	envelope.Data = data
+	return nil
+}
@@ -0,0 +1,57 @@
+package codec
+
+import (
+	"fmt"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+type Protobuf struct {
+	messages map[string]proto.Message
+}
+
+func NewProtobuf(messages map[string]proto.Message) *Protobuf {
+	return &Protobuf{messages}
+}
+
+func (c *Protobuf) ContentType() string {
+	return "application/protobuf"
+}
+
+func (c *Protobuf) Encode(envelope *events.Envelope) ([]byte, error) {
+	message, err := c.message(envelope)
+	if err != nil {
+		return nil, err
+	}
+	if err := protojson.Unmarshal(envelope.Data, message); err != nil {
+		return nil, err
+	}
+	return proto.Marshal(message)
+}
+
+func (c *Protobuf) Decode(data []byte, envelope *events.Envelope) error {
+	message, err := c.message(envelope)
+	if err != nil {
+		return err
+	}
+	if err := proto.Unmarshal(data, message); err != nil {
+		return err
+	}
+	value, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(message)
+	if err != nil {
+		return err
+	}
+	envelope.Data = value
+	return nil
+}
+
+func (c *Protobuf) message(envelope *events.Envelope) (proto.Message, error) {
+	key := SchemaKey(envelope.Type, envelope.SchemaVersion)
+	message, ok := c.messages[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSchema, key)
+	}
+	return message.ProtoReflect().New().Interface(), nil
+}
@@ -0,0 +1,72 @@
+package events
+
+import (
+	"context"
+	"time"
+)
+
+type detachedContext struct {
+	parent context.Context
+}
+
+func (c detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (c detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (c detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key any) any {
+	return c.parent.Value(key)
+}
+
+func detach(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
+
+type contextKey int
+
+const (
+	correlationIdKey contextKey = iota
+	causationIdKey
+	metadataKey
+	traceContextKey
+)
+
+func WithCorrelationId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIdKey, id)
+}
+
+func CorrelationId(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIdKey).(string)
+	return id
+}
+
+func WithCausationId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, causationIdKey, id)
+}
+
+func CausationId(ctx context.Context) string {
+	id, _ := ctx.Value(causationIdKey).(string)
+	return id
+}
+
+func WithMetadata(ctx context.Context, key, value string) context.Context {
+	metadata := map[string]string{key: value}
+	for k, v := range Metadata(ctx) {
+		if k != key {
+			metadata[k] = v
+		}
+	}
+	return context.WithValue(ctx, metadataKey, metadata)
+}
+
+func Metadata(ctx context.Context) map[string]string {
+	metadata, _ := ctx.Value(metadataKey).(map[string]string)
+	return metadata
+}
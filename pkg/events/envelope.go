@@ -0,0 +1,100 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const SpecVersion = "1.0"
+
+var ErrInvalidEnvelope = errors.New("invalid event envelope")
+
+type Envelope struct {
+	SpecVersion     string            `json:"specversion"`
+	Id              string            `json:"id"`
+	Type            string            `json:"type"`
+	Source          string            `json:"source"`
+	Time            time.Time         `json:"time"`
+	DataContentType string            `json:"datacontenttype"`
+	SchemaVersion   int               `json:"schemaversion"`
+	CorrelationId   string            `json:"correlationid"`
+	CausationId     string            `json:"causationid,omitempty"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+	TraceParent     string            `json:"traceparent,omitempty"`
+	TraceState      string            `json:"tracestate,omitempty"`
+	Data            json.RawMessage   `json:"data"`
+}
+
+func NewEnvelope(ctx context.Context, source string, event Event) (*Envelope, error) {
+	data, err := json.Marshal(event.GetPayload())
+	if err != nil {
+		return nil, err
+	}
+	id := EventId(event)
+	if id == "" {
+		id = uuid.NewString()
+	}
+	correlationId := CorrelationId(ctx)
+	if correlationId == "" {
+		correlationId = id
+	}
+	trace, ok := TraceContextFrom(ctx)
+	if ok {
+		trace = trace.Child()
+	} else {
+		trace = NewTraceContext()
+	}
+	return &Envelope{
+		SpecVersion:     SpecVersion,
+		Id:              id,
+		Type:            event.GetName(),
+		Source:          source,
+		Time:            event.GetDateTime().UTC(),
+		DataContentType: "application/json",
+		SchemaVersion:   EventVersion(event),
+		CorrelationId:   correlationId,
+		CausationId:     CausationId(ctx),
+		Metadata:        Metadata(ctx),
+		TraceParent:     trace.TraceParent(),
+		TraceState:      trace.State,
+		Data:            data,
+	}, nil
+}
+
+func ParseEnvelope(data []byte) (*Envelope, error) {
+	envelope := &Envelope{}
+	if err := json.Unmarshal(data, envelope); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEnvelope, err)
+	}
+	if envelope.SpecVersion != SpecVersion {
+		return nil, fmt.Errorf("%w: unsupported specversion %q", ErrInvalidEnvelope, envelope.SpecVersion)
+	}
+	if envelope.Id == "" || envelope.Type == "" || envelope.Source == "" {
+		return nil, fmt.Errorf("%w: id, type and source are required", ErrInvalidEnvelope)
+	}
+	return envelope, nil
+}
+
+func (e *Envelope) Decode(payload any) error {
+	return json.Unmarshal(e.Data, payload)
+}
+
+func (e *Envelope) Context(ctx context.Context) context.Context {
+	correlationId := e.CorrelationId
+	if correlationId == "" {
+		correlationId = e.Id
+	}
+	ctx = WithCausationId(WithCorrelationId(ctx, correlationId), e.Id)
+	for key, value := range e.Metadata {
+		ctx = WithMetadata(ctx, key, value)
+	}
+	if trace, err := ParseTraceParent(e.TraceParent, e.TraceState); err == nil {
+		ctx = WithTraceContext(ctx, trace.Child())
+	}
+	return ctx
+}
@@ -0,0 +1,66 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEnvelope(t *testing.T) {
+	event := NewTypedEvent("test.created", TestPayload{Amount: 10})
+	envelope, err := NewEnvelope(context.Background(), "walletcore", event)
+
+	assert.Nil(t, err)
+	assert.Equal(t, SpecVersion, envelope.SpecVersion)
+	assert.Equal(t, event.GetId(), envelope.Id)
+	assert.Equal(t, "test.created", envelope.Type)
+	assert.Equal(t, "walletcore", envelope.Source)
+	assert.Equal(t, "application/json", envelope.DataContentType)
+	assert.Equal(t, 1, envelope.SchemaVersion)
+	assert.Equal(t, event.GetId(), envelope.CorrelationId)
+	assert.Empty(t, envelope.CausationId)
+	assert.JSONEq(t, `{"Amount":10}`, string(envelope.Data))
+}
+
+func TestNewEnvelope_WithContext(t *testing.T) {
+	ctx := WithCausationId(WithCorrelationId(context.Background(), "correlation"), "cause")
+	ctx = WithMetadata(ctx, "tenant", "acme")
+	envelope, err := NewEnvelope(ctx, "walletcore", &TestEvent{Name: "test"})
+
+	assert.Nil(t, err)
+	assert.NotEmpty(t, envelope.Id)
+	assert.Equal(t, "correlation", envelope.CorrelationId)
+	assert.Equal(t, "cause", envelope.CausationId)
+	assert.Equal(t, map[string]string{"tenant": "acme"}, envelope.Metadata)
+}
+
+func TestParseEnvelope(t *testing.T) {
+	event := NewTypedEvent("test.created", TestPayload{Amount: 10})
+	envelope, _ := NewEnvelope(context.Background(), "walletcore", event)
+	data, _ := json.Marshal(envelope)
+
+	parsed, err := ParseEnvelope(data)
+	assert.Nil(t, err)
+	assert.Equal(t, envelope.Id, parsed.Id)
+	assert.True(t, envelope.Time.Equal(parsed.Time))
+	var payload TestPayload
+	assert.Nil(t, parsed.Decode(&payload))
+	assert.Equal(t, TestPayload{Amount: 10}, payload)
+
+	ctx := parsed.Context(context.Background())
+	assert.Equal(t, event.GetId(), CorrelationId(ctx))
+	assert.Equal(t, event.GetId(), CausationId(ctx))
+}
+
+func TestParseEnvelope_WithInvalidData(t *testing.T) {
+	_, err := ParseEnvelope([]byte(`{"from":"1","to":"2","amount":10}`))
+	assert.ErrorIs(t, err, ErrInvalidEnvelope)
+
+	_, err = ParseEnvelope([]byte(`{"specversion":"1.0","type":"test"}`))
+	assert.ErrorIs(t, err, ErrInvalidEnvelope)
+
+	_, err = ParseEnvelope([]byte(`not json`))
+	assert.ErrorIs(t, err, ErrInvalidEnvelope)
+}
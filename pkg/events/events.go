@@ -1,76 +1,305 @@
 package events
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+var (
+	ErrDispatcherClosed = errors.New("event dispatcher is shut down")
+	ErrQueueFull        = errors.New("event queue is full")
+)
+
 type Event interface {
 	GetName() string
 	GetDateTime() time.Time
 	GetPayload() interface{}
-	SetPayload(payload interface{})
+}
+
+type IdentifiedEvent interface {
+	GetId() string
+}
+
+type VersionedEvent interface {
+	GetVersion() int
+}
+
+type Dispatcher interface {
+	Dispatch(ctx context.Context, event Event) error
 }
 
 type EventHandler interface {
-	Handle(event Event, wg *sync.WaitGroup)
+	Handle(ctx context.Context, event Event) error
+}
+
+type NamedHandler interface {
+	Name() string
+}
+
+type HandlerError struct {
+	Event   string
+	Handler string
+	Err     error
+}
+
+func (e *HandlerError) Error() string {
+	return fmt.Sprintf("handler %s failed on %s: %s", e.Handler, e.Event, e.Err)
+}
+
+func (e *HandlerError) Unwrap() error {
+	return e.Err
+}
+
+func EventId(event Event) string {
+	if identified, ok := event.(IdentifiedEvent); ok {
+		return identified.GetId()
+	}
+	return ""
+}
+
+func EventVersion(event Event) int {
+	if versioned, ok := event.(VersionedEvent); ok {
+		return versioned.GetVersion()
+	}
+	return 1
+}
+
+func HandlerName(handler EventHandler) string {
+	if named, ok := handler.(NamedHandler); ok {
+		return named.Name()
+	}
+	return fmt.Sprintf("%T", handler)
+}
+
+type subscription struct {
+	name    string
+	pattern pattern
+	handler EventHandler
+	wrapped EventHandler
 }
 
 type EventDispatcher struct {
-	handlers map[string][]EventHandler
+	mu            sync.RWMutex
+	subscriptions []subscription
+	options       options
+	stateMu       sync.RWMutex
+	closed        bool
+	closing       chan struct{}
+	queue         chan queuedEvent
+	closeQueue    sync.Once
+	senders       sync.WaitGroup
+	inflight      sync.WaitGroup
+	workers       sync.WaitGroup
+	dropped       atomic.Int64
+}
+
+func NewEventDispatcher(opts ...Option) *EventDispatcher {
+	ed := &EventDispatcher{
+		options: defaultOptions(),
+		closing: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(&ed.options)
+	}
+	if ed.options.workers > 0 {
+		ed.queue = make(chan queuedEvent, ed.options.queueSize)
+		for i := 0; i < ed.options.workers; i++ {
+			ed.workers.Add(1)
+			go ed.work()
+		}
+	}
+	return ed
+}
+
+type queuedEvent struct {
+	ctx   context.Context
+	event Event
+}
+
+func (ed *EventDispatcher) Dispatch(ctx context.Context, event Event) error {
+	ed.stateMu.RLock()
+	if ed.closed {
+		ed.stateMu.RUnlock()
+		return ErrDispatcherClosed
+	}
+	if ed.queue == nil {
+		ed.inflight.Add(1)
+		ed.stateMu.RUnlock()
+		defer ed.inflight.Done()
+		return ed.dispatch(ctx, event)
+	}
+	ed.senders.Add(1)
+	ed.stateMu.RUnlock()
+	defer ed.senders.Done()
+	item := queuedEvent{detach(ctx), event}
+	switch ed.options.backpressure {
+	case Drop:
+		select {
+		case ed.queue <- item:
+		default:
+			ed.dropped.Add(1)
+		}
+	case Fail:
+		select {
+		case ed.queue <- item:
+		default:
+			return ErrQueueFull
+		}
+	default:
+		select {
+		case ed.queue <- item:
+		case <-ed.closing:
+			return ErrDispatcherClosed
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
 }
 
-func NewEventDispatcher() *EventDispatcher {
-	return &EventDispatcher{
-		handlers: make(map[string][]EventHandler),
+func (ed *EventDispatcher) Dropped() int64 {
+	return ed.dropped.Load()
+}
+
+func (ed *EventDispatcher) Shutdown(ctx context.Context) error {
+	ed.stateMu.Lock()
+	if !ed.closed {
+		ed.closed = true
+		close(ed.closing)
+	}
+	ed.stateMu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		// Blocked senders give up once closing is closed, so the queue can be
+		// closed without racing a send and the workers drain what is left.
+		ed.senders.Wait()
+		if ed.queue != nil {
+			ed.closeQueue.Do(func() { close(ed.queue) })
+		}
+		ed.inflight.Wait()
+		ed.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
 
-func (ed *EventDispatcher) Dispatch(event Event) {
-	if handlers, ok := ed.handlers[event.GetName()]; ok {
-		wg := &sync.WaitGroup{}
-		for _, handler := range handlers {
-			wg.Add(1)
-			go handler.Handle(event, wg)
+func (ed *EventDispatcher) work() {
+	defer ed.workers.Done()
+	for item := range ed.queue {
+		if err := ed.dispatch(item.ctx, item.event); err != nil {
+			ed.options.errorHandler(item.event, err)
 		}
-		wg.Wait()
 	}
 }
 
-func (ed *EventDispatcher) Register(name string, handler EventHandler) error {
-	if _, ok := ed.handlers[name]; ok {
-		for _, h := range ed.handlers[name] {
-			if h == handler {
-				return errors.New("handler already registered")
-			}
+func (ed *EventDispatcher) dispatch(ctx context.Context, event Event) error {
+	subs := ed.match(event.GetName())
+	if len(subs) == 0 {
+		return nil
+	}
+	errs := make([]error, len(subs))
+	if ed.options.sequential {
+		for i, sub := range subs {
+			errs[i] = sub.handle(ctx, event)
+		}
+		return errors.Join(errs...)
+	}
+	wg := &sync.WaitGroup{}
+	for i, sub := range subs {
+		wg.Add(1)
+		go func(i int, sub subscription) {
+			defer wg.Done()
+			errs[i] = sub.handle(ctx, event)
+		}(i, sub)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func (sub subscription) handle(ctx context.Context, event Event) error {
+	if err := sub.wrapped.Handle(ctx, event); err != nil {
+		return &HandlerError{
+			Event:   event.GetName(),
+			Handler: HandlerName(sub.handler),
+			Err:     err,
 		}
 	}
-	ed.handlers[name] = append(ed.handlers[name], handler)
 	return nil
 }
 
-func (ed *EventDispatcher) Has(name string, handler EventHandler) bool {
-	if _, ok := ed.handlers[name]; ok {
-		for _, h := range ed.handlers[name] {
-			if h == handler {
-				return true
-			}
+func (ed *EventDispatcher) match(name string) []subscription {
+	ed.mu.RLock()
+	defer ed.mu.RUnlock()
+	var subs []subscription
+	for _, sub := range ed.subscriptions {
+		if !sub.pattern.matches(name) || containsHandler(subs, sub.handler) {
+			continue
 		}
+		subs = append(subs, sub)
 	}
-	return false
+	return subs
+}
+
+func (ed *EventDispatcher) Register(name string, handler EventHandler, middlewares ...Middleware) error {
+	pattern, err := parsePattern(name)
+	if err != nil {
+		return err
+	}
+	ed.mu.Lock()
+	defer ed.mu.Unlock()
+	if ed.indexOf(name, handler) >= 0 {
+		return errors.New("handler already registered")
+	}
+	chain := append(append([]Middleware(nil), ed.options.middlewares...), middlewares...)
+	ed.subscriptions = append(ed.subscriptions, subscription{name, pattern, handler, Chain(handler, chain...)})
+	return nil
+}
+
+func (ed *EventDispatcher) Has(name string, handler EventHandler) bool {
+	ed.mu.RLock()
+	defer ed.mu.RUnlock()
+	return ed.indexOf(name, handler) >= 0
 }
 
 func (ed *EventDispatcher) Remove(name string, handler EventHandler) {
-	if _, ok := ed.handlers[name]; ok {
-		for i, h := range ed.handlers[name] {
-			if h == handler {
-				ed.handlers[name] = append(ed.handlers[name][:i], ed.handlers[name][i+1:]...)
-			}
-		}
+	ed.mu.Lock()
+	defer ed.mu.Unlock()
+	if i := ed.indexOf(name, handler); i >= 0 {
+		subscriptions := make([]subscription, 0, len(ed.subscriptions)-1)
+		subscriptions = append(subscriptions, ed.subscriptions[:i]...)
+		ed.subscriptions = append(subscriptions, ed.subscriptions[i+1:]...)
 	}
 }
 
 func (ed *EventDispatcher) Clear() {
-	ed.handlers = make(map[string][]EventHandler)
+	ed.mu.Lock()
+	defer ed.mu.Unlock()
+	ed.subscriptions = nil
+}
+
+func (ed *EventDispatcher) indexOf(name string, handler EventHandler) int {
+	for i, sub := range ed.subscriptions {
+		if sub.name == name && sub.handler == handler {
+			return i
+		}
+	}
+	return -1
+}
+
+func containsHandler(subs []subscription, handler EventHandler) bool {
+	for _, sub := range subs {
+		if sub.handler == handler {
+			return true
+		}
+	}
+	return false
 }
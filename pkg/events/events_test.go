@@ -0,0 +1,321 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestEvent struct {
+	Name    string
+	Payload interface{}
+}
+
+func (e *TestEvent) GetName() string {
+	return e.Name
+}
+
+func (e *TestEvent) GetPayload() interface{} {
+	return e.Payload
+}
+
+func (e *TestEvent) SetPayload(payload interface{}) {
+	e.Payload = payload
+}
+
+func (e *TestEvent) GetDateTime() time.Time {
+	return time.Now()
+}
+
+type TestEventHandler struct {
+	ID     int
+	Err    error
+	Called int
+}
+
+func (h *TestEventHandler) Handle(ctx context.Context, event Event) error {
+	h.Called++
+	return h.Err
+}
+
+type CountingEventHandler struct {
+	Called  atomic.Int64
+	Release chan struct{}
+}
+
+func (h *CountingEventHandler) Handle(ctx context.Context, event Event) error {
+	if h.Release != nil {
+		<-h.Release
+	}
+	h.Called.Add(1)
+	return nil
+}
+
+type OrderedEventHandler struct {
+	ID    int
+	Order *[]int
+}
+
+func (h *OrderedEventHandler) Handle(ctx context.Context, event Event) error {
+	*h.Order = append(*h.Order, h.ID)
+	return nil
+}
+
+type EventDispatcherTestSuite struct {
+	suite.Suite
+	event           TestEvent
+	event2          TestEvent
+	handler         TestEventHandler
+	handler2        TestEventHandler
+	handler3        TestEventHandler
+	eventDispatcher *EventDispatcher
+}
+
+func (suite *EventDispatcherTestSuite) SetupTest() {
+	suite.eventDispatcher = NewEventDispatcher()
+	suite.handler = TestEventHandler{ID: 1}
+	suite.handler2 = TestEventHandler{ID: 2}
+	suite.handler3 = TestEventHandler{ID: 3}
+	suite.event = TestEvent{Name: "test", Payload: "test"}
+	suite.event2 = TestEvent{Name: "test2", Payload: "test2"}
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatcher_Register() {
+	err := suite.eventDispatcher.Register(suite.event.GetName(), &suite.handler)
+	assert.Nil(suite.T(), err)
+	err = suite.eventDispatcher.Register(suite.event.GetName(), &suite.handler2)
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), suite.eventDispatcher.Has(suite.event.GetName(), &suite.handler))
+	assert.True(suite.T(), suite.eventDispatcher.Has(suite.event.GetName(), &suite.handler2))
+	assert.False(suite.T(), suite.eventDispatcher.Has(suite.event.GetName(), &suite.handler3))
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatcher_Register_WithSameHandler() {
+	err := suite.eventDispatcher.Register(suite.event.GetName(), &suite.handler)
+	assert.Nil(suite.T(), err)
+	err = suite.eventDispatcher.Register(suite.event.GetName(), &suite.handler)
+	assert.EqualError(suite.T(), err, "handler already registered")
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatcher_Remove() {
+	suite.eventDispatcher.Register(suite.event.GetName(), &suite.handler)
+	suite.eventDispatcher.Register(suite.event.GetName(), &suite.handler2)
+	suite.eventDispatcher.Remove(suite.event.GetName(), &suite.handler)
+	assert.False(suite.T(), suite.eventDispatcher.Has(suite.event.GetName(), &suite.handler))
+	assert.True(suite.T(), suite.eventDispatcher.Has(suite.event.GetName(), &suite.handler2))
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatcher_Clear() {
+	suite.eventDispatcher.Register(suite.event.GetName(), &suite.handler)
+	suite.eventDispatcher.Register(suite.event2.GetName(), &suite.handler2)
+	suite.eventDispatcher.Clear()
+	assert.False(suite.T(), suite.eventDispatcher.Has(suite.event.GetName(), &suite.handler))
+	assert.False(suite.T(), suite.eventDispatcher.Has(suite.event2.GetName(), &suite.handler2))
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatcher_Dispatch() {
+	suite.eventDispatcher.Register(suite.event.GetName(), &suite.handler)
+	suite.eventDispatcher.Register(suite.event.GetName(), &suite.handler2)
+	suite.eventDispatcher.Register(suite.event2.GetName(), &suite.handler3)
+	err := suite.eventDispatcher.Dispatch(context.Background(), &suite.event)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 1, suite.handler.Called)
+	assert.Equal(suite.T(), 1, suite.handler2.Called)
+	assert.Equal(suite.T(), 0, suite.handler3.Called)
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatcher_Dispatch_WithHandlerErrors() {
+	cause := errors.New("unable to publish")
+	suite.handler.Err = cause
+	suite.handler2.Err = errors.New("unable to save")
+	suite.eventDispatcher.Register(suite.event.GetName(), &suite.handler)
+	suite.eventDispatcher.Register(suite.event.GetName(), &suite.handler2)
+	suite.eventDispatcher.Register(suite.event.GetName(), &suite.handler3)
+	err := suite.eventDispatcher.Dispatch(context.Background(), &suite.event)
+	assert.ErrorIs(suite.T(), err, cause)
+	assert.EqualError(suite.T(), err,
+		"handler *events.TestEventHandler failed on test: unable to publish\n"+
+			"handler *events.TestEventHandler failed on test: unable to save")
+	var handlerErr *HandlerError
+	assert.ErrorAs(suite.T(), err, &handlerErr)
+	assert.Equal(suite.T(), "test", handlerErr.Event)
+	assert.Equal(suite.T(), 1, suite.handler3.Called)
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatcher_ConcurrentRegisterAndDispatch() {
+	handler := &CountingEventHandler{}
+	suite.eventDispatcher.Register(suite.event.GetName(), handler)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("event.%d", i)
+			h := &TestEventHandler{ID: i}
+			suite.eventDispatcher.Register(name, h)
+			suite.eventDispatcher.Has(name, h)
+			suite.eventDispatcher.Remove(name, h)
+		}(i)
+		go func() {
+			defer wg.Done()
+			suite.eventDispatcher.Dispatch(context.Background(), &suite.event)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(suite.T(), int64(50), handler.Called.Load())
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatcher_Async_ShutdownDrainsQueue() {
+	dispatcher := NewEventDispatcher(WithAsync(4, 100))
+	handler := &CountingEventHandler{}
+	dispatcher.Register(suite.event.GetName(), handler)
+	for i := 0; i < 100; i++ {
+		assert.Nil(suite.T(), dispatcher.Dispatch(context.Background(), &suite.event))
+	}
+	err := dispatcher.Shutdown(context.Background())
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), int64(100), handler.Called.Load())
+	assert.ErrorIs(suite.T(), dispatcher.Dispatch(context.Background(), &suite.event), ErrDispatcherClosed)
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatcher_Async_WithDropBackpressure() {
+	dispatcher := NewEventDispatcher(WithAsync(1, 1), WithBackpressure(Drop))
+	handler := &CountingEventHandler{Release: make(chan struct{})}
+	dispatcher.Register(suite.event.GetName(), handler)
+	for i := 0; i < 5; i++ {
+		assert.Nil(suite.T(), dispatcher.Dispatch(context.Background(), &suite.event))
+	}
+	close(handler.Release)
+	dispatcher.Shutdown(context.Background())
+	assert.Equal(suite.T(), int64(5), handler.Called.Load()+dispatcher.Dropped())
+	assert.GreaterOrEqual(suite.T(), dispatcher.Dropped(), int64(3))
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatcher_Async_WithFailBackpressure() {
+	dispatcher := NewEventDispatcher(WithAsync(1, 1), WithBackpressure(Fail))
+	handler := &CountingEventHandler{Release: make(chan struct{})}
+	dispatcher.Register(suite.event.GetName(), handler)
+	var err error
+	for i := 0; i < 5 && err == nil; i++ {
+		err = dispatcher.Dispatch(context.Background(), &suite.event)
+	}
+	assert.ErrorIs(suite.T(), err, ErrQueueFull)
+	close(handler.Release)
+	dispatcher.Shutdown(context.Background())
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatcher_Async_ReportsErrors() {
+	var reported atomic.Int64
+	dispatcher := NewEventDispatcher(WithAsync(2, 10), WithErrorHandler(func(event Event, err error) {
+		reported.Add(1)
+	}))
+	suite.handler.Err = errors.New("unable to publish")
+	dispatcher.Register(suite.event.GetName(), &suite.handler)
+	dispatcher.Dispatch(context.Background(), &suite.event)
+	dispatcher.Shutdown(context.Background())
+	assert.Equal(suite.T(), int64(1), reported.Load())
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatcher_Shutdown_WithExpiredContext() {
+	dispatcher := NewEventDispatcher(WithAsync(1, 1))
+	handler := &CountingEventHandler{Release: make(chan struct{})}
+	dispatcher.Register(suite.event.GetName(), handler)
+	dispatcher.Dispatch(context.Background(), &suite.event)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := dispatcher.Shutdown(ctx)
+	assert.ErrorIs(suite.T(), err, context.DeadlineExceeded)
+	close(handler.Release)
+	assert.Nil(suite.T(), dispatcher.Shutdown(context.Background()))
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatcher_Shutdown_WithBlockedDispatch() {
+	dispatcher := NewEventDispatcher(WithAsync(1, 1))
+	handler := &CountingEventHandler{Release: make(chan struct{})}
+	dispatcher.Register(suite.event.GetName(), handler)
+	dispatcher.Dispatch(context.Background(), &suite.event)
+	dispatcher.Dispatch(context.Background(), &suite.event)
+	blocked := make(chan error)
+	go func() {
+		blocked <- dispatcher.Dispatch(context.Background(), &suite.event)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(suite.T(), dispatcher.Shutdown(ctx), context.DeadlineExceeded)
+	assert.ErrorIs(suite.T(), <-blocked, ErrDispatcherClosed)
+	close(handler.Release)
+	assert.Nil(suite.T(), dispatcher.Shutdown(context.Background()))
+	assert.Equal(suite.T(), int64(2), handler.Called.Load())
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatcher_Register_WithInvalidPattern() {
+	for _, name := range []string{"", "transaction.", "transaction.cre*", "a..b"} {
+		err := suite.eventDispatcher.Register(name, &suite.handler)
+		assert.ErrorIs(suite.T(), err, ErrInvalidPattern, name)
+	}
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatcher_Dispatch_WithPatterns() {
+	cases := []struct {
+		pattern string
+		name    string
+		matches bool
+	}{
+		{"transaction.*", "transaction.created", true},
+		{"transaction.*", "transaction", false},
+		{"transaction.*", "transaction.created.v2", false},
+		{"*.created", "transaction.created", true},
+		{"*.created", "balances.updated", false},
+		{"#", "balances.updated", true},
+		{"#", "test", true},
+		{"transaction.#", "transaction", true},
+		{"transaction.#", "transaction.created.v2", true},
+		{"#.created", "payment_request.created", true},
+		{"payment_request.*.v2", "payment_request.created.v2", true},
+	}
+	for _, c := range cases {
+		dispatcher := NewEventDispatcher()
+		handler := &TestEventHandler{}
+		assert.Nil(suite.T(), dispatcher.Register(c.pattern, handler))
+		dispatcher.Dispatch(context.Background(), &TestEvent{Name: c.name})
+		assert.Equal(suite.T(), c.matches, handler.Called == 1, "%s ~ %s", c.pattern, c.name)
+	}
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatcher_Dispatch_WithOverlappingPatterns() {
+	suite.eventDispatcher.Register("#", &suite.handler)
+	suite.eventDispatcher.Register("test", &suite.handler)
+	suite.eventDispatcher.Register("*", &suite.handler)
+	suite.eventDispatcher.Register("*", &suite.handler2)
+	err := suite.eventDispatcher.Dispatch(context.Background(), &suite.event)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 1, suite.handler.Called)
+	assert.Equal(suite.T(), 1, suite.handler2.Called)
+
+	suite.eventDispatcher.Remove("#", &suite.handler)
+	assert.True(suite.T(), suite.eventDispatcher.Has("test", &suite.handler))
+	assert.False(suite.T(), suite.eventDispatcher.Has("#", &suite.handler))
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatcher_Dispatch_InRegistrationOrder() {
+	var order []int
+	dispatcher := NewEventDispatcher(WithSequentialHandlers())
+	dispatcher.Register("test", &OrderedEventHandler{ID: 1, Order: &order})
+	dispatcher.Register("#", &OrderedEventHandler{ID: 2, Order: &order})
+	dispatcher.Register("*", &OrderedEventHandler{ID: 3, Order: &order})
+	dispatcher.Register("test", &OrderedEventHandler{ID: 4, Order: &order})
+	dispatcher.Dispatch(context.Background(), &suite.event)
+	assert.Equal(suite.T(), []int{1, 2, 3, 4}, order)
+}
+
+func TestEventDispatcherTestSuite(t *testing.T) {
+	suite.Run(t, new(EventDispatcherTestSuite))
+}
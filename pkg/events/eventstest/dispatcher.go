@@ -0,0 +1,37 @@
+package eventstest
+
+import (
+	"context"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/stretchr/testify/assert"
+)
+
+type Dispatcher struct {
+	Recording
+}
+
+func NewDispatcher(t assert.TestingT) *Dispatcher {
+	return &Dispatcher{newRecording(t)}
+}
+
+func (d *Dispatcher) Dispatch(ctx context.Context, event events.Event) error {
+	return d.record(event)
+}
+
+type Handler struct {
+	Recording
+	name string
+}
+
+func NewHandler(t assert.TestingT, name string) *Handler {
+	return &Handler{newRecording(t), name}
+}
+
+func (h *Handler) Name() string {
+	return h.name
+}
+
+func (h *Handler) Handle(ctx context.Context, event events.Event) error {
+	return h.record(event)
+}
@@ -0,0 +1,85 @@
+package eventstest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/stretchr/testify/assert"
+)
+
+type testPayload struct {
+	Amount float64 `json:"amount"`
+}
+
+type fakeT struct {
+	errors []string
+}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestDispatcher_AssertDispatched(t *testing.T) {
+	d := NewDispatcher(t)
+	ctx := context.Background()
+	assert.Nil(t, d.Dispatch(ctx, events.NewTypedEvent("test.created", testPayload{10})))
+	assert.Nil(t, d.Dispatch(ctx, events.NewTypedEvent("test.updated", testPayload{20})))
+
+	event := d.AssertDispatched("test.updated", PayloadEquals(testPayload{20}))
+	assert.Equal(t, "test.updated", event.GetName())
+	d.AssertDispatched("test.created", Payload(func(p testPayload) bool { return p.Amount > 5 }))
+	d.AssertNotDispatched("test.deleted")
+	d.AssertCount("test.created", 1)
+	d.AssertOrder("test.created", "test.updated")
+	assert.Equal(t, []string{"test.created", "test.updated"}, d.Names())
+
+	d.Reset()
+	assert.Empty(t, d.Events())
+}
+
+func TestDispatcher_AssertDispatched_WithFailures(t *testing.T) {
+	ft := &fakeT{}
+	d := NewDispatcher(ft)
+	ctx := context.Background()
+	d.Dispatch(ctx, events.NewTypedEvent("test.created", testPayload{10}))
+	d.Dispatch(ctx, events.NewTypedEvent("test.updated", testPayload{20}))
+
+	assert.Nil(t, d.AssertDispatched("test.created", PayloadEquals(testPayload{20})))
+	assert.Nil(t, d.AssertDispatched("test.deleted"))
+	assert.False(t, d.AssertNotDispatched("test.created"))
+	assert.False(t, d.AssertOrder("test.updated", "test.created"))
+	assert.False(t, d.AssertCount("test.created", 2))
+	assert.Len(t, ft.errors, 5)
+}
+
+func TestDispatcher_FailWith(t *testing.T) {
+	d := NewDispatcher(t)
+	d.FailWith(errors.New("broker unavailable"))
+	err := d.Dispatch(context.Background(), events.NewTypedEvent("test.created", testPayload{10}))
+	assert.EqualError(t, err, "broker unavailable")
+	d.AssertDispatched("test.created")
+}
+
+func TestHandler_WaitFor(t *testing.T) {
+	ed := events.NewEventDispatcher(events.WithAsync(1, 10))
+	defer ed.Shutdown(context.Background())
+	h := NewHandler(t, "recorder")
+	assert.Nil(t, ed.Register("test.*", h))
+	assert.Equal(t, "recorder", events.HandlerName(h))
+
+	assert.Nil(t, ed.Dispatch(context.Background(), events.NewTypedEvent("test.created", json.RawMessage(`{"amount":10}`))))
+	event := h.WaitFor("test.created", time.Second, PayloadEquals(testPayload{10}))
+	assert.NotNil(t, event)
+}
+
+func TestHandler_WaitFor_WithTimeout(t *testing.T) {
+	ft := &fakeT{}
+	h := NewHandler(ft, "recorder")
+	assert.Nil(t, h.WaitFor("test.created", 10*time.Millisecond))
+	assert.Len(t, ft.errors, 1)
+}
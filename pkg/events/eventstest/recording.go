@@ -0,0 +1,182 @@
+package eventstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/stretchr/testify/assert"
+)
+
+type tHelper interface {
+	Helper()
+}
+
+type Matcher func(event events.Event) bool
+
+func Payload[T any](match func(payload T) bool) Matcher {
+	return func(event events.Event) bool {
+		switch payload := event.GetPayload().(type) {
+		case T:
+			return match(payload)
+		case json.RawMessage:
+			var decoded T
+			if err := json.Unmarshal(payload, &decoded); err != nil {
+				return false
+			}
+			return match(decoded)
+		default:
+			return false
+		}
+	}
+}
+
+func PayloadEquals[T any](expected T) Matcher {
+	return Payload(func(payload T) bool {
+		return reflect.DeepEqual(expected, payload)
+	})
+}
+
+type Recording struct {
+	t       assert.TestingT
+	mu      sync.Mutex
+	events  []events.Event
+	err     error
+	changed chan struct{}
+}
+
+func newRecording(t assert.TestingT) Recording {
+	return Recording{t: t, changed: make(chan struct{})}
+}
+
+func (r *Recording) FailWith(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.err = err
+}
+
+func (r *Recording) Events() []events.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]events.Event(nil), r.events...)
+}
+
+func (r *Recording) Names() []string {
+	var names []string
+	for _, event := range r.Events() {
+		names = append(names, event.GetName())
+	}
+	return names
+}
+
+func (r *Recording) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+	r.err = nil
+}
+
+func (r *Recording) AssertDispatched(name string, matchers ...Matcher) events.Event {
+	if h, ok := r.t.(tHelper); ok {
+		h.Helper()
+	}
+	if event := find(r.Events(), name, matchers); event != nil {
+		return event
+	}
+	assert.Fail(r.t, fmt.Sprintf("no matching %q event was dispatched", name), "dispatched: %s", strings.Join(r.Names(), ", "))
+	return nil
+}
+
+func (r *Recording) AssertNotDispatched(name string, matchers ...Matcher) bool {
+	if h, ok := r.t.(tHelper); ok {
+		h.Helper()
+	}
+	if event := find(r.Events(), name, matchers); event != nil {
+		return assert.Fail(r.t, fmt.Sprintf("unexpected %q event was dispatched", name))
+	}
+	return true
+}
+
+func (r *Recording) AssertCount(name string, expected int) bool {
+	if h, ok := r.t.(tHelper); ok {
+		h.Helper()
+	}
+	count := 0
+	for _, event := range r.Events() {
+		if event.GetName() == name {
+			count++
+		}
+	}
+	return assert.Equal(r.t, expected, count, "number of %q events", name)
+}
+
+func (r *Recording) AssertOrder(names ...string) bool {
+	if h, ok := r.t.(tHelper); ok {
+		h.Helper()
+	}
+	dispatched := r.Names()
+	next := 0
+	for _, name := range dispatched {
+		if next < len(names) && name == names[next] {
+			next++
+		}
+	}
+	if next < len(names) {
+		return assert.Fail(r.t, fmt.Sprintf("events were not dispatched in order %s", strings.Join(names, ", ")), "dispatched: %s", strings.Join(dispatched, ", "))
+	}
+	return true
+}
+
+func (r *Recording) WaitFor(name string, timeout time.Duration, matchers ...Matcher) events.Event {
+	if h, ok := r.t.(tHelper); ok {
+		h.Helper()
+	}
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		r.mu.Lock()
+		event := find(r.events, name, matchers)
+		changed := r.changed
+		r.mu.Unlock()
+		if event != nil {
+			return event
+		}
+		select {
+		case <-changed:
+		case <-deadline.C:
+			assert.Fail(r.t, fmt.Sprintf("no matching %q event was dispatched within %s", name, timeout), "dispatched: %s", strings.Join(r.Names(), ", "))
+			return nil
+		}
+	}
+}
+
+func (r *Recording) record(event events.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	close(r.changed)
+	r.changed = make(chan struct{})
+	return r.err
+}
+
+func find(recorded []events.Event, name string, matchers []Matcher) events.Event {
+	for _, event := range recorded {
+		if event.GetName() == name && matchesAll(event, matchers) {
+			return event
+		}
+	}
+	return nil
+}
+
+func matchesAll(event events.Event, matchers []Matcher) bool {
+	for _, match := range matchers {
+		if !match(event) {
+			return false
+		}
+	}
+	return true
+}
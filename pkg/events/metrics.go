@@ -0,0 +1,29 @@
+package events
+
+import (
+	"expvar"
+	"time"
+)
+
+type ExpvarMetrics struct {
+	calls    *expvar.Map
+	errors   *expvar.Map
+	duration *expvar.Map
+}
+
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	return &ExpvarMetrics{
+		calls:    expvar.NewMap(name + "_calls"),
+		errors:   expvar.NewMap(name + "_errors"),
+		duration: expvar.NewMap(name + "_duration_ms"),
+	}
+}
+
+func (m *ExpvarMetrics) Observe(event, handler string, duration time.Duration, err error) {
+	key := event + ":" + handler
+	m.calls.Add(key, 1)
+	if err != nil {
+		m.errors.Add(key, 1)
+	}
+	m.duration.AddFloat(key, float64(duration)/float64(time.Millisecond))
+}
@@ -0,0 +1,165 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+)
+
+var ErrHandlerTimeout = errors.New("event handler timed out")
+
+type Middleware func(next EventHandler) EventHandler
+
+type Backoff func(attempt int) time.Duration
+
+type MetricsRecorder interface {
+	Observe(event, handler string, duration time.Duration, err error)
+}
+
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("event handler panicked: %v", e.Value)
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+type middlewareHandler struct {
+	name   string
+	handle func(ctx context.Context, event Event) error
+}
+
+func (h *middlewareHandler) Name() string {
+	return h.name
+}
+
+func (h *middlewareHandler) Handle(ctx context.Context, event Event) error {
+	return h.handle(ctx, event)
+}
+
+func wrap(next EventHandler, handle func(ctx context.Context, event Event) error) EventHandler {
+	return &middlewareHandler{HandlerName(next), handle}
+}
+
+func Chain(handler EventHandler, middlewares ...Middleware) EventHandler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+func ExponentialBackoff(initial, max time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		delay := initial << attempt
+		if delay <= 0 || delay > max {
+			return max
+		}
+		return delay
+	}
+}
+
+func Retry(attempts int, backoff Backoff) Middleware {
+	return func(next EventHandler) EventHandler {
+		return wrap(next, func(ctx context.Context, event Event) error {
+			var err error
+			for attempt := 0; attempt < attempts; attempt++ {
+				if attempt > 0 {
+					timer := time.NewTimer(backoff(attempt - 1))
+					select {
+					case <-timer.C:
+					case <-ctx.Done():
+						timer.Stop()
+						return errors.Join(err, ctx.Err())
+					}
+				}
+				if err = next.Handle(ctx, event); err == nil || IsPermanent(err) {
+					return err
+				}
+			}
+			return err
+		})
+	}
+}
+
+func Timeout(timeout time.Duration) Middleware {
+	return func(next EventHandler) EventHandler {
+		return wrap(next, func(ctx context.Context, event Event) error {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			err := next.Handle(ctx, event)
+			if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("%w: %w", ErrHandlerTimeout, err)
+			}
+			return err
+		})
+	}
+}
+
+func Recover() Middleware {
+	return func(next EventHandler) EventHandler {
+		return wrap(next, func(ctx context.Context, event Event) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = &PanicError{Value: r, Stack: debug.Stack()}
+				}
+			}()
+			return next.Handle(ctx, event)
+		})
+	}
+}
+
+func Logging(logger *log.Logger) Middleware {
+	return func(next EventHandler) EventHandler {
+		name := HandlerName(next)
+		return wrap(next, func(ctx context.Context, event Event) error {
+			start := time.Now()
+			err := next.Handle(ctx, event)
+			if err != nil {
+				logger.Printf("level=error event=%s handler=%s duration=%s error=%q", event.GetName(), name, time.Since(start), err)
+			} else {
+				logger.Printf("level=info event=%s handler=%s duration=%s", event.GetName(), name, time.Since(start))
+			}
+			return err
+		})
+	}
+}
+
+func Metrics(recorder MetricsRecorder) Middleware {
+	return func(next EventHandler) EventHandler {
+		name := HandlerName(next)
+		return wrap(next, func(ctx context.Context, event Event) error {
+			start := time.Now()
+			err := next.Handle(ctx, event)
+			recorder.Observe(event.GetName(), name, time.Since(start), err)
+			return err
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type FlakyEventHandler struct {
+	Failures int
+	Err      error
+	Called   int
+}
+
+func (h *FlakyEventHandler) Handle(ctx context.Context, event Event) error {
+	h.Called++
+	if h.Called <= h.Failures {
+		return h.Err
+	}
+	return nil
+}
+
+type PanickingEventHandler struct{}
+
+func (h *PanickingEventHandler) Handle(ctx context.Context, event Event) error {
+	panic("boom")
+}
+
+type SlowEventHandler struct {
+	Delay         time.Duration
+	IgnoreContext bool
+	Finished      bool
+}
+
+func (h *SlowEventHandler) Handle(ctx context.Context, event Event) error {
+	if h.IgnoreContext {
+		time.Sleep(h.Delay)
+		h.Finished = true
+		return nil
+	}
+	select {
+	case <-time.After(h.Delay):
+		h.Finished = true
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type RecordingMetrics struct {
+	mu           sync.Mutex
+	Observations []string
+}
+
+func (m *RecordingMetrics) Observe(event, handler string, duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Observations = append(m.Observations, event+":"+handler)
+}
+
+func TestRetry(t *testing.T) {
+	handler := &FlakyEventHandler{Failures: 2, Err: errors.New("unable to publish")}
+	wrapped := Retry(3, ExponentialBackoff(time.Millisecond, 5*time.Millisecond))(handler)
+	err := wrapped.Handle(context.Background(), &TestEvent{Name: "test"})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, handler.Called)
+}
+
+func TestRetry_WithExhaustedAttempts(t *testing.T) {
+	handler := &FlakyEventHandler{Failures: 5, Err: errors.New("unable to publish")}
+	wrapped := Retry(3, ExponentialBackoff(time.Millisecond, 5*time.Millisecond))(handler)
+	err := wrapped.Handle(context.Background(), &TestEvent{Name: "test"})
+	assert.EqualError(t, err, "unable to publish")
+	assert.Equal(t, 3, handler.Called)
+}
+
+func TestRetry_WithPermanentError(t *testing.T) {
+	handler := &FlakyEventHandler{Failures: 5, Err: Permanent(errors.New("invalid payload"))}
+	wrapped := Retry(3, ExponentialBackoff(time.Millisecond, 5*time.Millisecond))(handler)
+	err := wrapped.Handle(context.Background(), &TestEvent{Name: "test"})
+	assert.True(t, IsPermanent(err))
+	assert.Equal(t, 1, handler.Called)
+}
+
+func TestRetry_WithCanceledContext(t *testing.T) {
+	handler := &FlakyEventHandler{Failures: 5, Err: errors.New("unable to publish")}
+	wrapped := Retry(3, ExponentialBackoff(time.Second, time.Second))(handler)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := wrapped.Handle(ctx, &TestEvent{Name: "test"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, handler.Called)
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(100*time.Millisecond, time.Second)
+	assert.Equal(t, 100*time.Millisecond, backoff(0))
+	assert.Equal(t, 200*time.Millisecond, backoff(1))
+	assert.Equal(t, 800*time.Millisecond, backoff(3))
+	assert.Equal(t, time.Second, backoff(4))
+	assert.Equal(t, time.Second, backoff(80))
+}
+
+func TestTimeout(t *testing.T) {
+	wrapped := Timeout(10 * time.Millisecond)(&SlowEventHandler{Delay: time.Second})
+	err := wrapped.Handle(context.Background(), &TestEvent{Name: "test"})
+	assert.ErrorIs(t, err, ErrHandlerTimeout)
+
+	wrapped = Timeout(time.Second)(&SlowEventHandler{})
+	assert.Nil(t, wrapped.Handle(context.Background(), &TestEvent{Name: "test"}))
+}
+
+func TestTimeout_WaitsForHandler(t *testing.T) {
+	handler := &SlowEventHandler{Delay: 50 * time.Millisecond, IgnoreContext: true}
+	wrapped := Timeout(10 * time.Millisecond)(handler)
+	assert.Nil(t, wrapped.Handle(context.Background(), &TestEvent{Name: "test"}))
+	assert.True(t, handler.Finished)
+}
+
+func TestTimeout_WithCanceledContext(t *testing.T) {
+	wrapped := Timeout(time.Second)(&SlowEventHandler{Delay: time.Second})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := wrapped.Handle(ctx, &TestEvent{Name: "test"})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRecover(t *testing.T) {
+	wrapped := Recover()(&PanickingEventHandler{})
+	err := wrapped.Handle(context.Background(), &TestEvent{Name: "test"})
+	var panicErr *PanicError
+	assert.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+}
+
+func TestLogging(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := log.New(buf, "", 0)
+	wrapped := Logging(logger)(&FlakyEventHandler{Failures: 1, Err: errors.New("unable to publish")})
+	wrapped.Handle(context.Background(), &TestEvent{Name: "test"})
+	assert.Contains(t, buf.String(), `level=error event=test handler=*events.FlakyEventHandler`)
+	assert.Contains(t, buf.String(), `error="unable to publish"`)
+}
+
+func TestEventDispatcher_WithMiddlewares(t *testing.T) {
+	metrics := &RecordingMetrics{}
+	dispatcher := NewEventDispatcher(WithMiddleware(Metrics(metrics), Recover()))
+	panicking := &PanickingEventHandler{}
+	flaky := &FlakyEventHandler{Failures: 1, Err: errors.New("unable to publish")}
+	dispatcher.Register("test", panicking)
+	dispatcher.Register("test", flaky, Retry(2, ExponentialBackoff(time.Millisecond, time.Millisecond)))
+	err := dispatcher.Dispatch(context.Background(), &TestEvent{Name: "test"})
+
+	var handlerErr *HandlerError
+	assert.ErrorAs(t, err, &handlerErr)
+	assert.Equal(t, "*events.PanickingEventHandler", handlerErr.Handler)
+	assert.Equal(t, 2, flaky.Called)
+	assert.ElementsMatch(t, []string{
+		"test:*events.PanickingEventHandler",
+		"test:*events.FlakyEventHandler",
+	}, metrics.Observations)
+	assert.True(t, dispatcher.Has("test", flaky))
+}
@@ -0,0 +1,61 @@
+package events
+
+import "log"
+
+type Backpressure int
+
+const (
+	Block Backpressure = iota
+	Drop
+	Fail
+)
+
+type options struct {
+	workers      int
+	queueSize    int
+	backpressure Backpressure
+	sequential   bool
+	middlewares  []Middleware
+	errorHandler func(event Event, err error)
+}
+
+type Option func(*options)
+
+func defaultOptions() options {
+	return options{
+		errorHandler: func(event Event, err error) {
+			log.Println(err.Error())
+		},
+	}
+}
+
+func WithAsync(workers, queueSize int) Option {
+	return func(o *options) {
+		o.workers = workers
+		o.queueSize = queueSize
+	}
+}
+
+func WithBackpressure(backpressure Backpressure) Option {
+	return func(o *options) {
+		o.backpressure = backpressure
+	}
+}
+
+func WithSequentialHandlers() Option {
+	return func(o *options) {
+		o.sequential = true
+	}
+}
+
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(o *options) {
+		o.middlewares = append(o.middlewares, middlewares...)
+	}
+}
+
+func WithErrorHandler(handler func(event Event, err error)) Option {
+	return func(o *options) {
+		o.errorHandler = handler
+	}
+}
@@ -0,0 +1,46 @@
+package events
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidPattern = errors.New("invalid event pattern")
+
+type pattern []string
+
+func parsePattern(name string) (pattern, error) {
+	segments := strings.Split(name, ".")
+	for _, segment := range segments {
+		if segment == "" {
+			return nil, ErrInvalidPattern
+		}
+		if segment != "*" && segment != "#" && strings.ContainsAny(segment, "*#") {
+			return nil, ErrInvalidPattern
+		}
+	}
+	return segments, nil
+}
+
+func (p pattern) matches(name string) bool {
+	return matchSegments(p, strings.Split(name, "."))
+}
+
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	switch pattern[0] {
+	case "#":
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	case "*":
+		return len(segments) > 0 && matchSegments(pattern[1:], segments[1:])
+	default:
+		return len(segments) > 0 && pattern[0] == segments[0] && matchSegments(pattern[1:], segments[1:])
+	}
+}
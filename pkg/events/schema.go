@@ -0,0 +1,88 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrUnknownSchemaVersion = errors.New("unknown event schema version")
+	ErrMissingUpcaster      = errors.New("missing event upcaster")
+)
+
+type Upcaster func(data json.RawMessage) (json.RawMessage, error)
+
+type schema struct {
+	current   int
+	upcasters map[int]Upcaster
+}
+
+type SchemaRegistry struct {
+	mu      sync.RWMutex
+	schemas map[string]*schema
+}
+
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		schemas: make(map[string]*schema),
+	}
+}
+
+func (r *SchemaRegistry) Register(eventType string, current int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schema(eventType).current = current
+}
+
+func (r *SchemaRegistry) RegisterUpcaster(eventType string, from int, upcaster Upcaster) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schema(eventType).upcasters[from] = upcaster
+}
+
+func (r *SchemaRegistry) Version(eventType string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if s, ok := r.schemas[eventType]; ok {
+		return s.current
+	}
+	return 1
+}
+
+func (r *SchemaRegistry) Upcast(envelope *Envelope) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.schemas[envelope.Type]
+	if !ok {
+		s = &schema{current: 1}
+	}
+	version := envelope.SchemaVersion
+	if version < 1 || version > s.current {
+		return fmt.Errorf("%w: %s v%d (current v%d)", ErrUnknownSchemaVersion, envelope.Type, version, s.current)
+	}
+	data := envelope.Data
+	for ; version < s.current; version++ {
+		upcaster, ok := s.upcasters[version]
+		if !ok {
+			return fmt.Errorf("%w: %s v%d to v%d", ErrMissingUpcaster, envelope.Type, version, version+1)
+		}
+		var err error
+		if data, err = upcaster(data); err != nil {
+			return fmt.Errorf("upcasting %s v%d: %w", envelope.Type, version, err)
+		}
+	}
+	envelope.Data = data
+	envelope.SchemaVersion = version
+	return nil
+}
+
+func (r *SchemaRegistry) schema(eventType string) *schema {
+	s, ok := r.schemas[eventType]
+	if !ok {
+		s = &schema{current: 1, upcasters: make(map[int]Upcaster)}
+		r.schemas[eventType] = s
+	}
+	return s
+}
@@ -0,0 +1,79 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func renameField(from, to string) Upcaster {
+	return func(data json.RawMessage) (json.RawMessage, error) {
+		fields := map[string]json.RawMessage{}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
+		fields[to] = fields[from]
+		delete(fields, from)
+		return json.Marshal(fields)
+	}
+}
+
+func TestSchemaRegistry_Upcast(t *testing.T) {
+	registry := NewSchemaRegistry()
+	registry.Register("test", 3)
+	registry.RegisterUpcaster("test", 1, renameField("value", "amount"))
+	registry.RegisterUpcaster("test", 2, renameField("amount", "total"))
+	assert.Equal(t, 3, registry.Version("test"))
+
+	envelope := &Envelope{Type: "test", SchemaVersion: 1, Data: json.RawMessage(`{"value":10}`)}
+	assert.Nil(t, registry.Upcast(envelope))
+	assert.Equal(t, 3, envelope.SchemaVersion)
+	assert.JSONEq(t, `{"total":10}`, string(envelope.Data))
+
+	envelope = &Envelope{Type: "test", SchemaVersion: 3, Data: json.RawMessage(`{"total":10}`)}
+	assert.Nil(t, registry.Upcast(envelope))
+	assert.JSONEq(t, `{"total":10}`, string(envelope.Data))
+}
+
+func TestSchemaRegistry_Upcast_WithUnknownVersion(t *testing.T) {
+	registry := NewSchemaRegistry()
+	registry.Register("test", 2)
+
+	err := registry.Upcast(&Envelope{Type: "test", SchemaVersion: 3})
+	assert.ErrorIs(t, err, ErrUnknownSchemaVersion)
+	err = registry.Upcast(&Envelope{Type: "test", SchemaVersion: 0})
+	assert.ErrorIs(t, err, ErrUnknownSchemaVersion)
+	err = registry.Upcast(&Envelope{Type: "unregistered", SchemaVersion: 2})
+	assert.ErrorIs(t, err, ErrUnknownSchemaVersion)
+	assert.Nil(t, registry.Upcast(&Envelope{Type: "unregistered", SchemaVersion: 1}))
+}
+
+func TestSchemaRegistry_Upcast_WithMissingUpcaster(t *testing.T) {
+	registry := NewSchemaRegistry()
+	registry.Register("test", 2)
+
+	envelope := &Envelope{Type: "test", SchemaVersion: 1, Data: json.RawMessage(`{}`)}
+	assert.ErrorIs(t, registry.Upcast(envelope), ErrMissingUpcaster)
+	assert.Equal(t, 1, envelope.SchemaVersion)
+}
+
+func TestSchemaRegistry_Upcast_WithFailingUpcaster(t *testing.T) {
+	registry := NewSchemaRegistry()
+	registry.Register("test", 2)
+	registry.RegisterUpcaster("test", 1, func(data json.RawMessage) (json.RawMessage, error) {
+		return nil, errors.New("malformed payload")
+	})
+
+	envelope := &Envelope{Type: "test", SchemaVersion: 1, Data: json.RawMessage(`{}`)}
+	assert.EqualError(t, registry.Upcast(envelope), "upcasting test v1: malformed payload")
+	assert.JSONEq(t, `{}`, string(envelope.Data))
+}
+
+func TestNewVersionedEvent(t *testing.T) {
+	event := NewVersionedEvent("test", 2, TestPayload{})
+	assert.Equal(t, 2, event.GetVersion())
+	assert.Equal(t, 2, EventVersion(event))
+	assert.Equal(t, 1, EventVersion(&TestEvent{Name: "test"}))
+}
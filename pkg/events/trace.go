@@ -0,0 +1,93 @@
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	TraceParentHeader = "traceparent"
+	TraceStateHeader  = "tracestate"
+)
+
+var ErrInvalidTraceParent = errors.New("invalid traceparent")
+
+type TraceContext struct {
+	TraceId string
+	SpanId  string
+	Flags   byte
+	State   string
+}
+
+func NewTraceContext() TraceContext {
+	return TraceContext{TraceId: randomHex(16), SpanId: randomHex(8), Flags: 1}
+}
+
+func ParseTraceParent(traceParent, traceState string) (TraceContext, error) {
+	parts := strings.Split(strings.TrimSpace(traceParent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return TraceContext{}, fmt.Errorf("%w: %q", ErrInvalidTraceParent, traceParent)
+	}
+	if _, err := hex.DecodeString(parts[0]); err != nil {
+		return TraceContext{}, fmt.Errorf("%w: %q", ErrInvalidTraceParent, traceParent)
+	}
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if err != nil || len(parts[3]) != 2 {
+		return TraceContext{}, fmt.Errorf("%w: %q", ErrInvalidTraceParent, traceParent)
+	}
+	trace := TraceContext{TraceId: parts[1], SpanId: parts[2], Flags: byte(flags), State: traceState}
+	if !trace.IsValid() {
+		return TraceContext{}, fmt.Errorf("%w: %q", ErrInvalidTraceParent, traceParent)
+	}
+	return trace, nil
+}
+
+func (t TraceContext) IsValid() bool {
+	return isTraceHex(t.TraceId, 32) && isTraceHex(t.SpanId, 16)
+}
+
+func (t TraceContext) Sampled() bool {
+	return t.Flags&1 == 1
+}
+
+func (t TraceContext) Child() TraceContext {
+	return TraceContext{TraceId: t.TraceId, SpanId: randomHex(8), Flags: t.Flags, State: t.State}
+}
+
+func (t TraceContext) TraceParent() string {
+	return fmt.Sprintf("00-%s-%s-%02x", t.TraceId, t.SpanId, t.Flags)
+}
+
+func WithTraceContext(ctx context.Context, trace TraceContext) context.Context {
+	return context.WithValue(ctx, traceContextKey, trace)
+}
+
+func TraceContextFrom(ctx context.Context) (TraceContext, bool) {
+	trace, ok := ctx.Value(traceContextKey).(TraceContext)
+	return trace, ok
+}
+
+func isTraceHex(s string, length int) bool {
+	if len(s) != length || strings.Trim(s, "0") == "" {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func randomHex(size int) string {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,57 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	trace, err := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "vendor=1")
+	assert.Nil(t, err)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", trace.TraceId)
+	assert.Equal(t, "00f067aa0ba902b7", trace.SpanId)
+	assert.True(t, trace.Sampled())
+	assert.Equal(t, "vendor=1", trace.State)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", trace.TraceParent())
+
+	child := trace.Child()
+	assert.Equal(t, trace.TraceId, child.TraceId)
+	assert.NotEqual(t, trace.SpanId, child.SpanId)
+	assert.True(t, child.IsValid())
+}
+
+func TestParseTraceParent_WithInvalidValue(t *testing.T) {
+	for _, traceParent := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	} {
+		_, err := ParseTraceParent(traceParent, "")
+		assert.ErrorIs(t, err, ErrInvalidTraceParent, traceParent)
+	}
+}
+
+func TestNewEnvelope_WithTraceContext(t *testing.T) {
+	envelope, err := NewEnvelope(context.Background(), "walletcore", &TestEvent{Name: "test"})
+	assert.Nil(t, err)
+	started, err := ParseTraceParent(envelope.TraceParent, envelope.TraceState)
+	assert.Nil(t, err)
+
+	ctx := envelope.Context(context.Background())
+	current, ok := TraceContextFrom(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, started.TraceId, current.TraceId)
+
+	next, err := NewEnvelope(ctx, "transactions", &TestEvent{Name: "test"})
+	assert.Nil(t, err)
+	continued, err := ParseTraceParent(next.TraceParent, next.TraceState)
+	assert.Nil(t, err)
+	assert.Equal(t, started.TraceId, continued.TraceId)
+	assert.NotEqual(t, current.SpanId, continued.SpanId)
+}
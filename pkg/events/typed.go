@@ -0,0 +1,114 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrUnexpectedPayload = errors.New("unexpected event payload")
+
+type TypedEvent[T any] struct {
+	id       string
+	name     string
+	version  int
+	dateTime time.Time
+	payload  T
+}
+
+func NewTypedEvent[T any](name string, payload T) *TypedEvent[T] {
+	return NewVersionedEvent(name, 1, payload)
+}
+
+func NewVersionedEvent[T any](name string, version int, payload T) *TypedEvent[T] {
+	return &TypedEvent[T]{
+		id:       uuid.NewString(),
+		name:     name,
+		version:  version,
+		dateTime: time.Now(),
+		payload:  payload,
+	}
+}
+
+func (e *TypedEvent[T]) GetId() string {
+	return e.id
+}
+
+func (e *TypedEvent[T]) GetName() string {
+	return e.name
+}
+
+func (e *TypedEvent[T]) GetVersion() int {
+	return e.version
+}
+
+func (e *TypedEvent[T]) GetDateTime() time.Time {
+	return e.dateTime
+}
+
+func (e *TypedEvent[T]) GetPayload() interface{} {
+	return e.payload
+}
+
+func (e *TypedEvent[T]) Payload() T {
+	return e.payload
+}
+
+type Handler[T any] interface {
+	Handle(ctx context.Context, event *TypedEvent[T]) error
+}
+
+type typedHandler[T any] struct {
+	handler Handler[T]
+}
+
+func AsEventHandler[T any](handler Handler[T]) EventHandler {
+	return typedHandler[T]{handler}
+}
+
+func (h typedHandler[T]) Name() string {
+	if named, ok := h.handler.(NamedHandler); ok {
+		return named.Name()
+	}
+	return fmt.Sprintf("%T", h.handler)
+}
+
+func (h typedHandler[T]) Handle(ctx context.Context, event Event) error {
+	typed, err := asTypedEvent[T](event)
+	if err != nil {
+		return Permanent(err)
+	}
+	return h.handler.Handle(ctx, typed)
+}
+
+func asTypedEvent[T any](event Event) (*TypedEvent[T], error) {
+	if typed, ok := event.(*TypedEvent[T]); ok {
+		return typed, nil
+	}
+	payload, ok := event.GetPayload().(T)
+	if !ok {
+		raw, isRaw := event.GetPayload().(json.RawMessage)
+		if !isRaw || json.Unmarshal(raw, &payload) != nil {
+			return nil, fmt.Errorf("%w: %s carries %T", ErrUnexpectedPayload, event.GetName(), event.GetPayload())
+		}
+	}
+	return &TypedEvent[T]{
+		id:       EventId(event),
+		name:     event.GetName(),
+		version:  EventVersion(event),
+		dateTime: event.GetDateTime(),
+		payload:  payload,
+	}, nil
+}
+
+func Subscribe[T any](ed *EventDispatcher, name string, handler Handler[T], middlewares ...Middleware) error {
+	return ed.Register(name, AsEventHandler(handler), middlewares...)
+}
+
+func Unsubscribe[T any](ed *EventDispatcher, name string, handler Handler[T]) {
+	ed.Remove(name, AsEventHandler(handler))
+}
@@ -0,0 +1,77 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestPayload struct {
+	Amount float64
+}
+
+type TestTypedHandler struct {
+	Payloads []TestPayload
+}
+
+func (h *TestTypedHandler) Name() string {
+	return "TestTypedHandler"
+}
+
+func (h *TestTypedHandler) Handle(ctx context.Context, event *TypedEvent[TestPayload]) error {
+	h.Payloads = append(h.Payloads, event.Payload())
+	return nil
+}
+
+func TestNewTypedEvent(t *testing.T) {
+	event := NewTypedEvent("test", TestPayload{Amount: 10})
+	assert.Equal(t, "test", event.GetName())
+	assert.Equal(t, TestPayload{Amount: 10}, event.Payload())
+	assert.Equal(t, TestPayload{Amount: 10}, event.GetPayload())
+	assert.False(t, event.GetDateTime().IsZero())
+}
+
+func TestSubscribe(t *testing.T) {
+	ed := NewEventDispatcher()
+	handler := &TestTypedHandler{}
+	untyped := &TestEventHandler{}
+	assert.Nil(t, Subscribe[TestPayload](ed, "test", handler))
+	assert.Nil(t, ed.Register("#", untyped))
+	assert.True(t, ed.Has("test", AsEventHandler[TestPayload](handler)))
+
+	assert.Nil(t, ed.Dispatch(context.Background(), NewTypedEvent("test", TestPayload{Amount: 10})))
+	assert.Nil(t, ed.Dispatch(context.Background(), &TestEvent{Name: "test", Payload: TestPayload{Amount: 20}}))
+	assert.Equal(t, []TestPayload{{Amount: 10}, {Amount: 20}}, handler.Payloads)
+	assert.Equal(t, 2, untyped.Called)
+
+	Unsubscribe[TestPayload](ed, "test", handler)
+	assert.False(t, ed.Has("test", AsEventHandler[TestPayload](handler)))
+}
+
+func TestSubscribe_WithUnexpectedPayload(t *testing.T) {
+	ed := NewEventDispatcher()
+	handler := &TestTypedHandler{}
+	assert.Nil(t, Subscribe[TestPayload](ed, "test", handler))
+
+	err := ed.Dispatch(context.Background(), &TestEvent{Name: "test", Payload: "invalid"})
+	assert.ErrorIs(t, err, ErrUnexpectedPayload)
+	assert.True(t, IsPermanent(err))
+	var handlerErr *HandlerError
+	assert.ErrorAs(t, err, &handlerErr)
+	assert.Equal(t, "TestTypedHandler", handlerErr.Handler)
+	assert.Empty(t, handler.Payloads)
+}
+
+func TestSubscribe_WithRawPayload(t *testing.T) {
+	ed := NewEventDispatcher()
+	handler := &TestTypedHandler{}
+	assert.Nil(t, Subscribe[TestPayload](ed, "test", handler))
+
+	assert.Nil(t, ed.Dispatch(context.Background(), &TestEvent{Name: "test", Payload: json.RawMessage(`{"Amount":10}`)}))
+	assert.Equal(t, []TestPayload{{Amount: 10}}, handler.Payloads)
+
+	err := ed.Dispatch(context.Background(), &TestEvent{Name: "test", Payload: json.RawMessage(`[]`)})
+	assert.ErrorIs(t, err, ErrUnexpectedPayload)
+}
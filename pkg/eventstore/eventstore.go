@@ -0,0 +1,59 @@
+package eventstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+)
+
+const AnyVersion = -1
+
+var ErrConcurrencyConflict = errors.New("stream version conflict")
+
+type StoredEvent struct {
+	Position int64
+	StreamId string
+	Version  int
+	Envelope *events.Envelope
+}
+
+type Store interface {
+	Append(ctx context.Context, streamId string, expectedVersion int, envelopes ...*events.Envelope) error
+	ReadStream(ctx context.Context, streamId string, fromVersion int) ([]*StoredEvent, error)
+	ReadAll(ctx context.Context, fromPosition int64, limit int) ([]*StoredEvent, error)
+}
+
+type RecordedEvent struct {
+	stored *StoredEvent
+}
+
+func NewRecordedEvent(stored *StoredEvent) *RecordedEvent {
+	return &RecordedEvent{stored}
+}
+
+func (e *RecordedEvent) GetId() string {
+	return e.stored.Envelope.Id
+}
+
+func (e *RecordedEvent) GetName() string {
+	return e.stored.Envelope.Type
+}
+
+func (e *RecordedEvent) GetVersion() int {
+	return e.stored.Envelope.SchemaVersion
+}
+
+func (e *RecordedEvent) GetDateTime() time.Time {
+	return e.stored.Envelope.Time
+}
+
+func (e *RecordedEvent) GetPayload() interface{} {
+	return json.RawMessage(e.stored.Envelope.Data)
+}
+
+func (e *RecordedEvent) Stored() *StoredEvent {
+	return e.stored
+}
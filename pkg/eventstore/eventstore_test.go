@@ -0,0 +1,163 @@
+package eventstore
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type testPayload struct {
+	Amount float64 `json:"amount"`
+}
+
+type testHandler struct {
+	payloads []testPayload
+	causes   []string
+}
+
+func (h *testHandler) Handle(ctx context.Context, event *events.TypedEvent[testPayload]) error {
+	h.payloads = append(h.payloads, event.Payload())
+	h.causes = append(h.causes, events.CausationId(ctx))
+	return nil
+}
+
+type EventStoreTestSuite struct {
+	suite.Suite
+	ctx   context.Context
+	store *MemoryStore
+}
+
+func (suite *EventStoreTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.store = NewMemoryStore()
+}
+
+func (suite *EventStoreTestSuite) envelope(amount float64) *events.Envelope {
+	envelope, err := events.NewEnvelope(suite.ctx, "test", events.NewTypedEvent("test.created", testPayload{amount}))
+	suite.Nil(err)
+	return envelope
+}
+
+func (suite *EventStoreTestSuite) TestAppend() {
+	suite.Nil(suite.store.Append(suite.ctx, "a", 0, suite.envelope(1), suite.envelope(2)))
+	suite.Nil(suite.store.Append(suite.ctx, "b", AnyVersion, suite.envelope(3)))
+	suite.Nil(suite.store.Append(suite.ctx, "a", 2, suite.envelope(4)))
+
+	stream, err := suite.store.ReadStream(suite.ctx, "a", 2)
+	suite.Nil(err)
+	suite.Len(stream, 2)
+	suite.Equal(2, stream[0].Version)
+	suite.Equal(int64(2), stream[0].Position)
+	suite.Equal(3, stream[1].Version)
+	suite.Equal(int64(4), stream[1].Position)
+}
+
+func (suite *EventStoreTestSuite) TestAppend_WithConcurrencyConflict() {
+	suite.Nil(suite.store.Append(suite.ctx, "a", 0, suite.envelope(1)))
+	err := suite.store.Append(suite.ctx, "a", 0, suite.envelope(2))
+	suite.ErrorIs(err, ErrConcurrencyConflict)
+
+	stream, _ := suite.store.ReadStream(suite.ctx, "a", 0)
+	suite.Len(stream, 1)
+}
+
+func (suite *EventStoreTestSuite) TestAppend_WithRecordedEvent() {
+	envelope := suite.envelope(1)
+	suite.Nil(suite.store.Append(suite.ctx, "a", 0, envelope))
+	suite.Nil(suite.store.Append(suite.ctx, "a", 0, envelope))
+
+	stream, _ := suite.store.ReadStream(suite.ctx, "a", 0)
+	suite.Len(stream, 1)
+}
+
+func (suite *EventStoreTestSuite) TestReadAll() {
+	suite.Nil(suite.store.Append(suite.ctx, "a", AnyVersion, suite.envelope(1), suite.envelope(2)))
+	suite.Nil(suite.store.Append(suite.ctx, "b", AnyVersion, suite.envelope(3)))
+
+	all, err := suite.store.ReadAll(suite.ctx, 2, 1)
+	suite.Nil(err)
+	suite.Len(all, 1)
+	suite.Equal("a", all[0].StreamId)
+	suite.Equal(int64(2), all[0].Position)
+
+	all, _ = suite.store.ReadAll(suite.ctx, 1, 0)
+	suite.Len(all, 3)
+}
+
+func (suite *EventStoreTestSuite) TestRecorder() {
+	ed := events.NewEventDispatcher()
+	suite.Nil(ed.Register("#", NewRecorder(suite.store, "test", nil)))
+	ctx := events.WithCorrelationId(suite.ctx, "flow")
+
+	event := events.NewTypedEvent("test.created", testPayload{10})
+	suite.Nil(ed.Dispatch(ctx, event))
+
+	stream, _ := suite.store.ReadStream(suite.ctx, "test.created-"+event.GetId(), 0)
+	suite.Len(stream, 1)
+	suite.Equal("test", stream[0].Envelope.Source)
+	suite.Equal("flow", stream[0].Envelope.CorrelationId)
+	suite.JSONEq(`{"amount":10}`, string(stream[0].Envelope.Data))
+}
+
+func (suite *EventStoreTestSuite) TestRecorder_ByAggregate() {
+	ed := events.NewEventDispatcher()
+	key := func(event events.Event) []byte {
+		if payload, ok := event.GetPayload().(testPayload); ok && payload.Amount > 0 {
+			return []byte("positive")
+		}
+		return nil
+	}
+	suite.Nil(ed.Register("#", NewRecorder(suite.store, "test", ByAggregate(key))))
+
+	suite.Nil(ed.Dispatch(suite.ctx, events.NewTypedEvent("test.created", testPayload{10})))
+	suite.Nil(ed.Dispatch(suite.ctx, events.NewTypedEvent("test.created", testPayload{20})))
+	negative := events.NewTypedEvent("test.created", testPayload{-5})
+	suite.Nil(ed.Dispatch(suite.ctx, negative))
+
+	stream, _ := suite.store.ReadStream(suite.ctx, "test.created-positive", 0)
+	suite.Len(stream, 2)
+	suite.Equal(2, stream[1].Version)
+	stream, _ = suite.store.ReadStream(suite.ctx, "test.created-"+negative.GetId(), 0)
+	suite.Len(stream, 1)
+}
+
+func (suite *EventStoreTestSuite) TestReplay() {
+	first, second := suite.envelope(1), suite.envelope(2)
+	suite.Nil(suite.store.Append(suite.ctx, "a", AnyVersion, first, second))
+	ed := events.NewEventDispatcher()
+	handler := &testHandler{}
+	suite.Nil(events.Subscribe[testPayload](ed, "test.created", handler))
+	suite.Nil(ed.Register("#", NewRecorder(suite.store, "test", nil)))
+
+	position, err := Replay(suite.ctx, suite.store, ed, 1)
+	suite.Nil(err)
+	suite.Equal(int64(2), position)
+	suite.Equal([]testPayload{{1}, {2}}, handler.payloads)
+	suite.Equal([]string{first.Id, second.Id}, handler.causes)
+
+	all, _ := suite.store.ReadAll(suite.ctx, 1, 0)
+	suite.Len(all, 2)
+
+	position, err = Replay(suite.ctx, suite.store, ed, 2)
+	suite.Nil(err)
+	suite.Equal(int64(2), position)
+	suite.Len(handler.payloads, 3)
+}
+
+func TestEventStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(EventStoreTestSuite))
+}
+
+func TestRecordedEvent(t *testing.T) {
+	envelope, _ := events.NewEnvelope(context.Background(), "test", events.NewVersionedEvent("test.created", 2, testPayload{10}))
+	event := NewRecordedEvent(&StoredEvent{Position: 1, StreamId: "a", Version: 1, Envelope: envelope})
+
+	assert.Equal(t, envelope.Id, events.EventId(event))
+	assert.Equal(t, "test.created", event.GetName())
+	assert.Equal(t, 2, events.EventVersion(event))
+	assert.JSONEq(t, `{"amount":10}`, string(event.GetPayload().(json.RawMessage)))
+}
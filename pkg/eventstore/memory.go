@@ -0,0 +1,82 @@
+package eventstore
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+)
+
+type MemoryStore struct {
+	mu      sync.RWMutex
+	events  []*StoredEvent
+	streams map[string][]*StoredEvent
+	ids     map[string]bool
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		streams: make(map[string][]*StoredEvent),
+		ids:     make(map[string]bool),
+	}
+}
+
+func (s *MemoryStore) Append(ctx context.Context, streamId string, expectedVersion int, envelopes ...*events.Envelope) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var pending []*events.Envelope
+	for _, envelope := range envelopes {
+		if !s.ids[envelope.Id] {
+			pending = append(pending, envelope)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	version := len(s.streams[streamId])
+	if expectedVersion != AnyVersion && expectedVersion != version {
+		return fmt.Errorf("%w: %s is at version %d, expected %d", ErrConcurrencyConflict, streamId, version, expectedVersion)
+	}
+	for _, envelope := range pending {
+		version++
+		stored := &StoredEvent{
+			Position: int64(len(s.events) + 1),
+			StreamId: streamId,
+			Version:  version,
+			Envelope: envelope,
+		}
+		s.events = append(s.events, stored)
+		s.streams[streamId] = append(s.streams[streamId], stored)
+		s.ids[envelope.Id] = true
+	}
+	return nil
+}
+
+func (s *MemoryStore) ReadStream(ctx context.Context, streamId string, fromVersion int) ([]*StoredEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var stored []*StoredEvent
+	for _, event := range s.streams[streamId] {
+		if event.Version >= fromVersion {
+			stored = append(stored, event)
+		}
+	}
+	return stored, nil
+}
+
+func (s *MemoryStore) ReadAll(ctx context.Context, fromPosition int64, limit int) ([]*StoredEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var stored []*StoredEvent
+	for _, event := range s.events {
+		if event.Position < fromPosition {
+			continue
+		}
+		if limit > 0 && len(stored) == limit {
+			break
+		}
+		stored = append(stored, event)
+	}
+	return stored, nil
+}
@@ -0,0 +1,59 @@
+package eventstore
+
+import (
+	"context"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+)
+
+type StreamFunc func(event events.Event) string
+
+func ByEventName(event events.Event) string {
+	return event.GetName()
+}
+
+// ByAggregate appends each event to the stream of the aggregate returned by
+// key, named "<event name>-<key>", so appends for different aggregates never
+// contend for the same stream version. Events without a key are written to a
+// stream of their own, named after their id.
+func ByAggregate(key func(event events.Event) []byte) StreamFunc {
+	return func(event events.Event) string {
+		if key != nil {
+			if id := key(event); len(id) > 0 {
+				return event.GetName() + "-" + string(id)
+			}
+		}
+		if id := events.EventId(event); id != "" {
+			return event.GetName() + "-" + id
+		}
+		return event.GetName()
+	}
+}
+
+type Recorder struct {
+	store  Store
+	source string
+	stream StreamFunc
+}
+
+func NewRecorder(store Store, source string, stream StreamFunc) *Recorder {
+	if stream == nil {
+		stream = ByAggregate(nil)
+	}
+	return &Recorder{store, source, stream}
+}
+
+func (r *Recorder) Name() string {
+	return "EventStoreRecorder"
+}
+
+func (r *Recorder) Handle(ctx context.Context, event events.Event) error {
+	if IsReplay(ctx) {
+		return nil
+	}
+	envelope, err := events.NewEnvelope(ctx, r.source, event)
+	if err != nil {
+		return events.Permanent(err)
+	}
+	return r.store.Append(ctx, r.stream(event), AnyVersion, envelope)
+}
@@ -0,0 +1,36 @@
+package eventstore
+
+import (
+	"context"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+)
+
+const replayBatchSize = 100
+
+type replayKey struct{}
+
+func IsReplay(ctx context.Context) bool {
+	replay, _ := ctx.Value(replayKey{}).(bool)
+	return replay
+}
+
+func Replay(ctx context.Context, store Store, dispatcher events.Dispatcher, fromPosition int64) (int64, error) {
+	position := fromPosition - 1
+	for {
+		batch, err := store.ReadAll(ctx, position+1, replayBatchSize)
+		if err != nil {
+			return position, err
+		}
+		for _, stored := range batch {
+			eventCtx := context.WithValue(stored.Envelope.Context(ctx), replayKey{}, true)
+			if err := dispatcher.Dispatch(eventCtx, NewRecordedEvent(stored)); err != nil {
+				return position, err
+			}
+			position = stored.Position
+		}
+		if len(batch) < replayBatchSize {
+			return position, nil
+		}
+	}
+}
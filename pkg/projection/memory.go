@@ -0,0 +1,79 @@
+package projection
+
+import (
+	"context"
+	"sync"
+)
+
+type memoryCheckpoint struct {
+	position  int64
+	processed map[string]bool
+}
+
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]*memoryCheckpoint
+	locks       map[string]*sync.Mutex
+}
+
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{
+		checkpoints: make(map[string]*memoryCheckpoint),
+		locks:       make(map[string]*sync.Mutex),
+	}
+}
+
+func (s *MemoryCheckpointStore) Position(ctx context.Context, name string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.checkpoint(name).position, nil
+}
+
+func (s *MemoryCheckpointStore) Process(ctx context.Context, name, eventId string, position int64, apply func(ctx context.Context) error) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	checkpoint := s.checkpoint(name)
+	applied := !checkpoint.processed[eventId]
+	if applied {
+		if err := apply(ctx); err != nil {
+			return false, err
+		}
+		checkpoint.processed[eventId] = true
+	}
+	if position > checkpoint.position {
+		checkpoint.position = position
+	}
+	return applied, nil
+}
+
+func (s *MemoryCheckpointStore) Reset(ctx context.Context, name string, reset func(ctx context.Context) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := reset(ctx); err != nil {
+		return err
+	}
+	delete(s.checkpoints, name)
+	return nil
+}
+
+func (s *MemoryCheckpointStore) Lock(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	s.mu.Lock()
+	lock, ok := s.locks[name]
+	if !ok {
+		lock = &sync.Mutex{}
+		s.locks[name] = lock
+	}
+	s.mu.Unlock()
+	lock.Lock()
+	defer lock.Unlock()
+	return fn(ctx)
+}
+
+func (s *MemoryCheckpointStore) checkpoint(name string) *memoryCheckpoint {
+	checkpoint, ok := s.checkpoints[name]
+	if !ok {
+		checkpoint = &memoryCheckpoint{processed: make(map[string]bool)}
+		s.checkpoints[name] = checkpoint
+	}
+	return checkpoint
+}
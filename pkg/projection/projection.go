@@ -0,0 +1,136 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/josimarz/fc-eda-challenge/pkg/eventstore"
+)
+
+const (
+	catchUpBatchSize = 100
+	// gapTimeout is how long a hole in the event store positions may stay
+	// open before it is treated as a rolled back append and skipped.
+	gapTimeout = 10 * time.Second
+)
+
+var ErrLocked = errors.New("projection is locked by another process")
+
+type Projection interface {
+	Name() string
+	Apply(ctx context.Context, envelope *events.Envelope) error
+	Reset(ctx context.Context) error
+}
+
+type CheckpointStore interface {
+	Position(ctx context.Context, name string) (int64, error)
+	Process(ctx context.Context, name, eventId string, position int64, apply func(ctx context.Context) error) (bool, error)
+	Reset(ctx context.Context, name string, reset func(ctx context.Context) error) error
+	Lock(ctx context.Context, name string, fn func(ctx context.Context) error) error
+}
+
+type Runner struct {
+	projection  Projection
+	checkpoints CheckpointStore
+	now         func() time.Time
+}
+
+func NewRunner(projection Projection, checkpoints CheckpointStore) *Runner {
+	return &Runner{projection, checkpoints, time.Now}
+}
+
+func (r *Runner) Name() string {
+	return "Projection(" + r.projection.Name() + ")"
+}
+
+func (r *Runner) Handle(ctx context.Context, event events.Event) error {
+	if eventstore.IsReplay(ctx) {
+		return nil
+	}
+	envelope, err := events.NewEnvelope(ctx, "", event)
+	if err != nil {
+		return events.Permanent(err)
+	}
+	return r.HandleEnvelope(ctx, envelope)
+}
+
+func (r *Runner) HandleEnvelope(ctx context.Context, envelope *events.Envelope) error {
+	_, err := r.process(ctx, envelope, 0)
+	return err
+}
+
+func (r *Runner) Position(ctx context.Context) (int64, error) {
+	return r.checkpoints.Position(ctx, r.projection.Name())
+}
+
+func (r *Runner) CatchUp(ctx context.Context, store eventstore.Store) (applied int, err error) {
+	err = r.checkpoints.Lock(ctx, r.projection.Name(), func(ctx context.Context) error {
+		applied, err = r.catchUp(ctx, store)
+		return err
+	})
+	return applied, err
+}
+
+func (r *Runner) Reset(ctx context.Context) error {
+	return r.checkpoints.Lock(ctx, r.projection.Name(), r.reset)
+}
+
+func (r *Runner) Rebuild(ctx context.Context, store eventstore.Store) (applied int, err error) {
+	err = r.checkpoints.Lock(ctx, r.projection.Name(), func(ctx context.Context) error {
+		if err := r.reset(ctx); err != nil {
+			return err
+		}
+		applied, err = r.catchUp(ctx, store)
+		return err
+	})
+	return applied, err
+}
+
+// catchUp applies the events after the checkpoint in position order. Positions
+// come from an auto-increment column, so a transaction that is still open can
+// commit a lower position than one already visible; catchUp stops at such a gap
+// until it is filled or older than gapTimeout, so the checkpoint never moves
+// past an event that may still appear.
+func (r *Runner) catchUp(ctx context.Context, store eventstore.Store) (int, error) {
+	position, err := r.Position(ctx)
+	if err != nil {
+		return 0, err
+	}
+	applied := 0
+	for {
+		batch, err := store.ReadAll(ctx, position+1, catchUpBatchSize)
+		if err != nil {
+			return applied, err
+		}
+		for _, stored := range batch {
+			if stored.Position > position+1 && r.now().Sub(stored.Envelope.Time) < gapTimeout {
+				return applied, nil
+			}
+			ok, err := r.process(ctx, stored.Envelope, stored.Position)
+			if err != nil {
+				return applied, fmt.Errorf("projecting %s at position %d: %w", r.projection.Name(), stored.Position, err)
+			}
+			if ok {
+				applied++
+			}
+			position = stored.Position
+		}
+		if len(batch) < catchUpBatchSize {
+			return applied, nil
+		}
+	}
+}
+
+func (r *Runner) reset(ctx context.Context) error {
+	return r.checkpoints.Reset(ctx, r.projection.Name(), r.projection.Reset)
+}
+
+func (r *Runner) process(ctx context.Context, envelope *events.Envelope, position int64) (bool, error) {
+	apply := func(ctx context.Context) error {
+		return r.projection.Apply(ctx, envelope)
+	}
+	return r.checkpoints.Process(ctx, r.projection.Name(), envelope.Id, position, apply)
+}
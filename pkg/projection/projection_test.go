@@ -0,0 +1,171 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/josimarz/fc-eda-challenge/pkg/events"
+	"github.com/josimarz/fc-eda-challenge/pkg/eventstore"
+	"github.com/stretchr/testify/suite"
+)
+
+type testPayload struct {
+	Amount float64 `json:"amount"`
+}
+
+type totalProjection struct {
+	total float64
+	err   error
+}
+
+func (p *totalProjection) Name() string {
+	return "total"
+}
+
+func (p *totalProjection) Apply(ctx context.Context, envelope *events.Envelope) error {
+	if p.err != nil {
+		return p.err
+	}
+	payload := testPayload{}
+	if err := envelope.Decode(&payload); err != nil {
+		return err
+	}
+	p.total += payload.Amount
+	return nil
+}
+
+func (p *totalProjection) Reset(ctx context.Context) error {
+	p.total = 0
+	return nil
+}
+
+type gapStore struct {
+	eventstore.Store
+	events []*eventstore.StoredEvent
+}
+
+func (s *gapStore) ReadAll(ctx context.Context, fromPosition int64, limit int) ([]*eventstore.StoredEvent, error) {
+	var stored []*eventstore.StoredEvent
+	for _, event := range s.events {
+		if event.Position >= fromPosition {
+			stored = append(stored, event)
+		}
+	}
+	return stored, nil
+}
+
+type ProjectionTestSuite struct {
+	suite.Suite
+	ctx        context.Context
+	store      *eventstore.MemoryStore
+	projection *totalProjection
+	runner     *Runner
+}
+
+func (suite *ProjectionTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.store = eventstore.NewMemoryStore()
+	suite.projection = &totalProjection{}
+	suite.runner = NewRunner(suite.projection, NewMemoryCheckpointStore())
+}
+
+func (suite *ProjectionTestSuite) append(amounts ...float64) {
+	for _, amount := range amounts {
+		envelope, err := events.NewEnvelope(suite.ctx, "test", events.NewTypedEvent("test.created", testPayload{amount}))
+		suite.Nil(err)
+		suite.Nil(suite.store.Append(suite.ctx, "test", eventstore.AnyVersion, envelope))
+	}
+}
+
+func (suite *ProjectionTestSuite) TestCatchUp() {
+	suite.append(1, 2, 3)
+	applied, err := suite.runner.CatchUp(suite.ctx, suite.store)
+	suite.Nil(err)
+	suite.Equal(3, applied)
+	suite.Equal(float64(6), suite.projection.total)
+
+	suite.append(4)
+	applied, err = suite.runner.CatchUp(suite.ctx, suite.store)
+	suite.Nil(err)
+	suite.Equal(1, applied)
+	suite.Equal(float64(10), suite.projection.total)
+	position, _ := suite.runner.Position(suite.ctx)
+	suite.Equal(int64(4), position)
+}
+
+func (suite *ProjectionTestSuite) TestCatchUp_WithFailingProjection() {
+	suite.append(1, 2)
+	suite.projection.err = errors.New("read model unavailable")
+	_, err := suite.runner.CatchUp(suite.ctx, suite.store)
+	suite.EqualError(err, "projecting total at position 1: read model unavailable")
+	position, _ := suite.runner.Position(suite.ctx)
+	suite.Equal(int64(0), position)
+
+	suite.projection.err = nil
+	applied, err := suite.runner.CatchUp(suite.ctx, suite.store)
+	suite.Nil(err)
+	suite.Equal(2, applied)
+	suite.Equal(float64(3), suite.projection.total)
+}
+
+func (suite *ProjectionTestSuite) TestCatchUp_WithGap() {
+	store := &gapStore{}
+	add := func(position int64, amount float64) {
+		envelope, err := events.NewEnvelope(suite.ctx, "test", events.NewTypedEvent("test.created", testPayload{amount}))
+		suite.Nil(err)
+		store.events = append(store.events, &eventstore.StoredEvent{Position: position, StreamId: "test", Envelope: envelope})
+	}
+	add(1, 1)
+	add(3, 3)
+	applied, err := suite.runner.CatchUp(suite.ctx, store)
+	suite.Nil(err)
+	suite.Equal(1, applied)
+	position, _ := suite.runner.Position(suite.ctx)
+	suite.Equal(int64(1), position)
+
+	add(2, 2)
+	store.events[1], store.events[2] = store.events[2], store.events[1]
+	add(5, 5)
+	applied, err = suite.runner.CatchUp(suite.ctx, store)
+	suite.Nil(err)
+	suite.Equal(2, applied)
+	suite.Equal(float64(6), suite.projection.total)
+
+	suite.runner.now = func() time.Time { return time.Now().Add(gapTimeout) }
+	applied, err = suite.runner.CatchUp(suite.ctx, store)
+	suite.Nil(err)
+	suite.Equal(1, applied)
+	position, _ = suite.runner.Position(suite.ctx)
+	suite.Equal(int64(5), position)
+}
+
+func (suite *ProjectionTestSuite) TestHandle_IsIdempotent() {
+	ed := events.NewEventDispatcher()
+	suite.Nil(ed.Register("test.*", suite.runner))
+	event := events.NewTypedEvent("test.created", testPayload{5})
+	suite.Nil(ed.Dispatch(suite.ctx, event))
+	suite.Nil(ed.Dispatch(suite.ctx, event))
+	suite.Equal(float64(5), suite.projection.total)
+
+	envelope, _ := events.NewEnvelope(suite.ctx, "test", event)
+	suite.Nil(suite.runner.HandleEnvelope(suite.ctx, envelope))
+	suite.Equal(float64(5), suite.projection.total)
+}
+
+func (suite *ProjectionTestSuite) TestRebuild() {
+	suite.append(1, 2)
+	_, err := suite.runner.CatchUp(suite.ctx, suite.store)
+	suite.Nil(err)
+	suite.projection.total = 100
+
+	applied, err := suite.runner.Rebuild(suite.ctx, suite.store)
+	suite.Nil(err)
+	suite.Equal(2, applied)
+	suite.Equal(float64(3), suite.projection.total)
+}
+
+func TestProjectionTestSuite(t *testing.T) {
+	suite.Run(t, new(ProjectionTestSuite))
+}
@@ -8,4 +8,22 @@ create table `transaction` (
     `created_at` datetime not null,
     `updated_at` datetime not null,
     primary key (`id`)
+);
+
+create table `event_store` (
+    `position` bigint not null auto_increment,
+    `stream_id` varchar(255) not null,
+    `version` int not null,
+    `id` char(36) not null,
+    `type` varchar(255) not null,
+    `schema_version` int not null,
+    `source` varchar(255) not null,
+    `correlation_id` varchar(255) not null,
+    `causation_id` varchar(255) not null,
+    `metadata` json not null,
+    `payload` json not null,
+    `occurred_at` datetime(6) not null,
+    primary key (`position`),
+    unique key `event_store_stream_version` (`stream_id`, `version`),
+    unique key `event_store_id` (`id`)
 );
\ No newline at end of file
@@ -4,6 +4,8 @@ create table `customer` (
     `id` char(36) not null,
     `name` varchar(255) not null,
     `email` varchar(255) not null,
+    `phone` varchar(16) not null default '',
+    `tax_id` varchar(14) not null default '',
     `created_at` datetime not null,
     `updated_at` datetime not null,
     primary key (`id`)
@@ -13,12 +15,103 @@ create table `account` (
     `id` char(36) not null,
     `customer_id` char(36) not null,
     `balance` decimal(10, 5) not null,
+    `version` int not null default 0,
     `created_at` datetime not null,
     `updated_at` datetime not null,
     primary key (`id`),
     foreign key (`customer_id`) references `customer`(`id`)
 );
 
+create table `key` (
+    `id` char(36) not null,
+    `type` varchar(16) not null,
+    `value` varchar(255) not null,
+    `account_id` char(36) not null,
+    `created_at` datetime not null,
+    `updated_at` datetime not null,
+    primary key (`id`),
+    unique key (`value`),
+    foreign key (`account_id`) references `account`(`id`)
+);
+
+create table `payment_request` (
+    `id` char(36) not null,
+    `requester_id` char(36) not null,
+    `payer_id` char(36) not null,
+    `amount` decimal(10, 5) not null,
+    `memo` varchar(255) not null,
+    `status` varchar(16) not null,
+    `expires_at` datetime not null,
+    `created_at` datetime not null,
+    `updated_at` datetime not null,
+    primary key (`id`),
+    key (`status`, `expires_at`),
+    foreign key (`requester_id`) references `account`(`id`),
+    foreign key (`payer_id`) references `account`(`id`)
+);
+
+create table `account_snapshot` (
+    `account_id` char(36) not null,
+    `customer_id` char(36) not null,
+    `balance` decimal(10, 5) not null,
+    `version` int not null,
+    `created_at` datetime(6) not null,
+    `updated_at` datetime(6) not null,
+    primary key (`account_id`),
+    foreign key (`account_id`) references `account`(`id`)
+);
+
+create table `processed_message` (
+    `id` char(36) not null,
+    `processed_at` datetime(6) not null,
+    primary key (`id`)
+);
+
+create table `event_store` (
+    `position` bigint not null auto_increment,
+    `stream_id` varchar(255) not null,
+    `version` int not null,
+    `id` char(36) not null,
+    `type` varchar(255) not null,
+    `schema_version` int not null,
+    `source` varchar(255) not null,
+    `correlation_id` varchar(255) not null,
+    `causation_id` varchar(255) not null,
+    `metadata` json not null,
+    `payload` json not null,
+    `occurred_at` datetime(6) not null,
+    primary key (`position`),
+    unique key `event_store_stream_version` (`stream_id`, `version`),
+    unique key `event_store_id` (`id`)
+);
+
+create table `projection_checkpoint` (
+    `name` varchar(255) not null,
+    `position` bigint not null,
+    `updated_at` datetime(6) not null,
+    primary key (`name`)
+);
+
+create table `projection_event` (
+    `name` varchar(255) not null,
+    `event_id` char(36) not null,
+    primary key (`name`, `event_id`)
+);
+
+create table `customer_balance` (
+    `customer_id` char(36) not null,
+    `accounts` int not null,
+    `total_balance` decimal(10, 5) not null,
+    `updated_at` datetime(6) not null,
+    primary key (`customer_id`)
+);
+
+create table `customer_balance_account` (
+    `account_id` char(36) not null,
+    `customer_id` char(36) not null,
+    primary key (`account_id`)
+);
+
 -- Customer 1
 
 set @customerId := uuid();